@@ -0,0 +1,339 @@
+// Package accounts keeps the cash and instrument holdings of users, and what of them is reserved for open orders.
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MichalPitr/exchange/journal"
+	"github.com/MichalPitr/exchange/ledger"
+)
+
+// Cash is the asset that instruments are bought with. Other assets are named after the symbol of their instrument.
+const Cash = "CASH"
+
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// Balance is how much of an asset a user has.
+type Balance struct {
+	Total    int64
+	Reserved int64 // Held for open orders.
+}
+
+func (b Balance) Available() int64 { return b.Total - b.Reserved }
+
+// transfer is a deposit, or a withdrawal if Amount is negative, as kept in the journal.
+type transfer struct {
+	UserId int32  `json:"userId"`
+	Asset  string `json:"asset"`
+	Amount int64  `json:"amount"`
+	Time   int64  `json:"time,omitempty"`
+}
+
+func (t transfer) postings() []ledger.Posting {
+	return []ledger.Posting{
+		{Account: ledger.UserAccount(t.UserId), Asset: t.Asset, Amount: t.Amount},
+		{Account: ledger.External, Asset: t.Asset, Amount: -t.Amount},
+	}
+}
+
+// Accounts holds the balances of every user. It's shared by the markets, each of which reserves and settles through
+// its own Market from its own goroutine.
+type Accounts struct {
+	mutex     sync.Mutex
+	balances  map[int32]map[string]*Balance
+	journal   *journal.Journal // Deposits and withdrawals, nil keeps them in memory only.
+	ledger    *ledger.Ledger   // Every balance change apart from reservations, nil if there's no ledger.
+	transfers uint64           // Number of deposits and withdrawals so far.
+}
+
+// New returns accounts that are kept in memory only.
+func New() *Accounts {
+	return &Accounts{balances: make(map[int32]map[string]*Balance)}
+}
+
+// Open restores the deposits and withdrawals journaled in filename, and journals the following ones there. What
+// trades moved between accounts, and what open orders reserve, is restored by the markets. Balance changes are posted
+// to the ledger in ledgerFilename, unless it's empty. Changes that didn't make it there before a restart are posted
+// as they are restored.
+func Open(filename, ledgerFilename string) (*Accounts, error) {
+	a := New()
+	var err error
+	if ledgerFilename != "" {
+		if a.ledger, err = ledger.Open(ledgerFilename); err != nil {
+			return nil, err
+		}
+	}
+	_, err = journal.Replay(filename, func(record []byte) error {
+		var t transfer
+		if err := json.Unmarshal(record, &t); err != nil {
+			return err
+		}
+		return a.apply(t)
+	})
+	if err == nil || errors.Is(err, os.ErrNotExist) {
+		a.journal, err = journal.Open(filename)
+	}
+	if err != nil {
+		a.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *Accounts) Close() error {
+	var errs []error
+	if a.journal != nil {
+		errs = append(errs, a.journal.Close())
+	}
+	if a.ledger != nil {
+		errs = append(errs, a.ledger.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// balance returns the balance of asset held by userId, creating it if needed. The caller holds the mutex.
+func (a *Accounts) balance(userId int32, asset string) *Balance {
+	assets, ok := a.balances[userId]
+	if !ok {
+		assets = make(map[string]*Balance)
+		a.balances[userId] = assets
+	}
+	b, ok := assets[asset]
+	if !ok {
+		b = &Balance{}
+		assets[asset] = b
+	}
+	return b
+}
+
+// Deposit adds amount of asset to the account of userId once it's durable.
+func (a *Accounts) Deposit(userId int32, asset string, amount int64) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.transfer(transfer{UserId: userId, Asset: asset, Amount: amount})
+}
+
+// Withdraw takes amount of asset out of the account of userId, unless that much of it isn't available.
+func (a *Accounts) Withdraw(userId int32, asset string, amount int64) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if available := a.balance(userId, asset).Available(); available < amount {
+		return fmt.Errorf("%w: %d %s available", ErrInsufficientFunds, available, asset)
+	}
+	return a.transfer(transfer{UserId: userId, Asset: asset, Amount: -amount})
+}
+
+// transfer journals t and applies it, and makes its posting durable. The caller holds the mutex.
+func (a *Accounts) transfer(t transfer) error {
+	t.Time = time.Now().UnixNano()
+	// What was posted before has to be durable before t is, or t would be posted ahead of it after a restart.
+	if a.ledger != nil {
+		if err := a.ledger.Sync(); err != nil {
+			return err
+		}
+	}
+	if a.journal != nil {
+		record, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		if err := a.journal.Append(record); err != nil {
+			return err
+		}
+		if err := a.journal.Sync(); err != nil {
+			return err
+		}
+	}
+	if err := a.apply(t); err != nil {
+		return err
+	}
+	if a.ledger != nil {
+		return a.ledger.Sync()
+	}
+	return nil
+}
+
+// apply adds a journaled transfer to the balance, and posts it. The caller holds the mutex.
+func (a *Accounts) apply(t transfer) error {
+	a.balance(t.UserId, t.Asset).Total += t.Amount
+	a.transfers++
+	if a.ledger == nil {
+		return nil
+	}
+	return a.ledger.PostTransfer(a.transfers, t.Time, t.postings())
+}
+
+// Users returns the ids of the users that have a balance of any asset, in increasing order.
+func (a *Accounts) Users() []int32 {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	users := make([]int32, 0, len(a.balances))
+	for userId := range a.balances {
+		users = append(users, userId)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i] < users[j] })
+	return users
+}
+
+// Balances returns the balances of userId by asset, in the order of their names.
+func (a *Accounts) Balances(userId int32) ([]string, []Balance) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	assets := make([]string, 0, len(a.balances[userId]))
+	for asset := range a.balances[userId] {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	balances := make([]Balance, len(assets))
+	for i, asset := range assets {
+		balances[i] = *a.balances[userId][asset]
+	}
+	return assets, balances
+}
+
+// Position is what the trades of a market added to an account, in cash and in holdings of the market's instrument.
+type Position struct {
+	Cash   int64 `json:"cash"`
+	Amount int64 `json:"amount"`
+}
+
+type hold struct {
+	userId int32
+	asset  string
+	amount int64
+}
+
+// Market reserves and settles for the orders of one market. It keeps what the market's trades added to every account
+// and what each open order holds, so that the market can snapshot them and they add up after a restart. It's only used
+// by the market's goroutine.
+type Market struct {
+	accounts  *Accounts
+	symbol    string
+	positions map[int32]Position
+	holds     map[uint64]hold
+}
+
+func (a *Accounts) Market(symbol string) *Market {
+	return &Market{accounts: a, symbol: symbol, positions: make(map[int32]Position), holds: make(map[uint64]hold)}
+}
+
+// Reserve holds amount more of asset for an order. If check is set it fails, and holds nothing, when the user doesn't
+// have that much available.
+func (m *Market) Reserve(orderId uint64, userId int32, asset string, amount int64, check bool) bool {
+	m.accounts.mutex.Lock()
+	defer m.accounts.mutex.Unlock()
+
+	b := m.accounts.balance(userId, asset)
+	if check && b.Available() < amount {
+		return false
+	}
+	b.Reserved += amount
+	h := m.holds[orderId]
+	m.holds[orderId] = hold{userId, asset, h.amount + amount}
+	return true
+}
+
+// Held returns how much an order holds.
+func (m *Market) Held(orderId uint64) int64 {
+	return m.holds[orderId].amount
+}
+
+// Hold lowers how much an order that reserved before holds, releasing the difference. Holds only grow through Reserve,
+// so holding more than the order holds keeps what it holds. Holding 0 forgets the order.
+func (m *Market) Hold(orderId uint64, amount int64) {
+	h, ok := m.holds[orderId]
+	if !ok || amount > h.amount {
+		return
+	}
+	m.accounts.mutex.Lock()
+	m.accounts.balance(h.userId, h.asset).Reserved += amount - h.amount
+	m.accounts.mutex.Unlock()
+
+	if amount == 0 {
+		delete(m.holds, orderId)
+		return
+	}
+	h.amount = amount
+	m.holds[orderId] = h
+}
+
+// Settle pays the seller of a trade from the cash of the buyer, and hands the amount traded to the buyer, atomically for
+// both of them and together with posting it to the ledger. Both of them pay their fee in cash, a negative fee is a
+// rebate. What the orders reserved for it is released separately.
+func (m *Market) Settle(tradeId uint64, time int64, buyerId, sellerId int32, amount int32, price int64, buyerFee, sellerFee int64) error {
+	cost := int64(amount) * price
+	postings := []ledger.Posting{
+		{Account: ledger.UserAccount(buyerId), Asset: Cash, Amount: -cost - buyerFee},
+		{Account: ledger.UserAccount(sellerId), Asset: Cash, Amount: cost - sellerFee},
+		{Account: ledger.UserAccount(sellerId), Asset: m.symbol, Amount: -int64(amount)},
+		{Account: ledger.UserAccount(buyerId), Asset: m.symbol, Amount: int64(amount)},
+	}
+	if buyerFee != 0 || sellerFee != 0 {
+		postings = append(postings, ledger.Posting{Account: ledger.Fees, Asset: Cash, Amount: buyerFee + sellerFee})
+	}
+
+	m.accounts.mutex.Lock()
+	defer m.accounts.mutex.Unlock()
+	if m.accounts.ledger != nil {
+		if err := m.accounts.ledger.PostTrade(m.symbol, tradeId, time, postings); err != nil {
+			return err
+		}
+	}
+	m.accounts.balance(buyerId, Cash).Total -= cost + buyerFee
+	m.accounts.balance(buyerId, m.symbol).Total += int64(amount)
+	m.accounts.balance(sellerId, Cash).Total += cost - sellerFee
+	m.accounts.balance(sellerId, m.symbol).Total -= int64(amount)
+	m.move(buyerId, -cost-buyerFee, int64(amount))
+	m.move(sellerId, cost-sellerFee, -int64(amount))
+	return nil
+}
+
+// Sync makes what was posted to the ledger so far durable. Markets sync before they acknowledge the commands that
+// traded, and before they snapshot, as trades covered by a snapshot aren't replayed, and so not posted again, after a
+// restart.
+func (m *Market) Sync() error {
+	m.accounts.mutex.Lock()
+	defer m.accounts.mutex.Unlock()
+
+	if m.accounts.ledger == nil {
+		return nil
+	}
+	return m.accounts.ledger.Sync()
+}
+
+func (m *Market) move(userId int32, cash, amount int64) {
+	p := m.positions[userId]
+	p.Cash += cash
+	p.Amount += amount
+	m.positions[userId] = p
+}
+
+// Positions returns a copy of what the trades of the market added to every account.
+func (m *Market) Positions() map[int32]Position {
+	positions := make(map[int32]Position, len(m.positions))
+	for userId, p := range m.positions {
+		positions[userId] = p
+	}
+	return positions
+}
+
+// Restore adds positions, as returned by Positions before a restart, to the accounts.
+func (m *Market) Restore(positions map[int32]Position) {
+	m.accounts.mutex.Lock()
+	defer m.accounts.mutex.Unlock()
+
+	for userId, p := range positions {
+		m.accounts.balance(userId, Cash).Total += p.Cash
+		m.accounts.balance(userId, m.symbol).Total += p.Amount
+		m.move(userId, p.Cash, p.Amount)
+	}
+}
@@ -0,0 +1,132 @@
+package accounts
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/MichalPitr/exchange/ledger"
+)
+
+func balance(a *Accounts, userId int32, asset string) Balance {
+	assets, balances := a.Balances(userId)
+	for i := range assets {
+		if assets[i] == asset {
+			return balances[i]
+		}
+	}
+	return Balance{}
+}
+
+func TestReserveChecksAvailableBalance(t *testing.T) {
+	a := New()
+	a.Deposit(1, Cash, 1000)
+	m := a.Market("AAPL")
+
+	if !m.Reserve(10, 1, Cash, 600, true) {
+		t.Fatal("Expected 600 of 1000 to be reserved")
+	}
+	if m.Reserve(11, 1, Cash, 600, true) {
+		t.Fatal("Expected only 400 to be available")
+	}
+	if err := a.Withdraw(1, Cash, 500); !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("Expected withdrawing reserved cash to fail, but got %v", err)
+	}
+
+	m.Hold(10, 0)
+	if b := balance(a, 1, Cash); b != (Balance{Total: 1000}) {
+		t.Errorf("Expected the reservation to be released, but got %+v", b)
+	}
+}
+
+func TestSettle(t *testing.T) {
+	a := New()
+	a.Deposit(1, Cash, 1000)
+	a.Deposit(2, "AAPL", 10)
+	m := a.Market("AAPL")
+	m.Reserve(10, 1, Cash, 500, true)
+	m.Reserve(11, 2, "AAPL", 5, true)
+
+	// The buyer pays a fee of 2, the seller gets a rebate of 1.
+	m.Settle(1, 0, 1, 2, 5, 90, 2, -1)
+	m.Hold(10, 0)
+	m.Hold(11, 0)
+
+	expected := map[int32]map[string]Balance{
+		1: {Cash: {Total: 548}, "AAPL": {Total: 5}},
+		2: {Cash: {Total: 451}, "AAPL": {Total: 5}},
+	}
+	for userId, assets := range expected {
+		for asset, want := range assets {
+			if got := balance(a, userId, asset); got != want {
+				t.Errorf("Expected user %d to have %+v %s, but got %+v", userId, want, asset, got)
+			}
+		}
+	}
+
+	// The positions restore the trade on top of the deposits.
+	restored := New()
+	restored.Deposit(1, Cash, 1000)
+	restored.Market("AAPL").Restore(m.Positions())
+	if got := balance(restored, 1, Cash); got.Total != 548 {
+		t.Errorf("Expected restored cash 548, but got %+v", got)
+	}
+}
+
+func TestTransfersAreJournaled(t *testing.T) {
+	dir := t.TempDir()
+	filename, ledgerFilename := filepath.Join(dir, "accounts.journal"), filepath.Join(dir, "ledger")
+	a, err := Open(filename, ledgerFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Deposit(1, Cash, 1000)
+	a.Withdraw(1, Cash, 300)
+	a.Close()
+
+	reopened, err := Open(filename, ledgerFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := balance(reopened, 1, Cash); got.Total != 700 {
+		t.Errorf("Expected 700 cash after restart, but got %+v", got)
+	}
+	reopened.Close()
+
+	report, err := ledger.Reconcile(ledgerFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Entries != 2 || report.Supply[Cash] != 700 || len(report.Problems) != 0 {
+		t.Errorf("Expected the deposit and the withdrawal to be posted once, but got %+v", report)
+	}
+}
+
+func TestLedgerKeepsTradesBeforeLaterTransfers(t *testing.T) {
+	dir := t.TempDir()
+	filename, ledgerFilename := filepath.Join(dir, "accounts.journal"), filepath.Join(dir, "ledger")
+	a, err := Open(filename, ledgerFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Deposit(1, Cash, 1000)
+	a.Deposit(2, "AAPL", 10)
+	a.Market("AAPL").Settle(1, 0, 1, 2, 10, 100, 0, 0)
+	if err := a.Withdraw(2, Cash, 1000); err != nil {
+		t.Fatal(err)
+	}
+	// Crash without closing, and so without syncing what the withdrawal didn't.
+	crashed, err := Open(filename, ledgerFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crashed.Close()
+
+	report, err := ledger.Reconcile(ledgerFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Entries != 4 || len(report.Problems) != 0 {
+		t.Errorf("Expected the trade to be posted before the withdrawal of its proceeds, but got %+v", report)
+	}
+}
@@ -2,31 +2,39 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"math/rand"
+	"strings"
 	"sync"
 	"time"
 
 	pb "github.com/MichalPitr/exchange/protos"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 func main() {
+	clientCount := flag.Int("clients", 1000, "Number of concurrent clients")
+	requestsPerClient := flag.Int("requests", 100, "Number of requests per client")
+	symbolList := flag.String("symbols", "AAPL", "Comma separated symbols to spread the load across")
+	flag.Parse()
+	symbols := strings.Split(*symbolList, ",")
+
 	var wg sync.WaitGroup
-	clientCount := 1000      // Number of concurrent clients
-	requestsPerClient := 100 // Number of requests per client
 
 	t0 := time.Now().UnixMilli()
 
-	for i := 0; i < clientCount; i++ {
+	for i := 0; i < *clientCount; i++ {
 		wg.Add(1)
 		go func(clientID int) {
 			defer wg.Done()
 			if clientID%2 == 0 {
-				simulateClient(clientID, requestsPerClient, "BUY")
+				simulateClient(clientID, *requestsPerClient, pb.Side_SIDE_BUY, symbols)
 			} else {
-				simulateClient(clientID, requestsPerClient, "SELL")
+				simulateClient(clientID, *requestsPerClient, pb.Side_SIDE_SELL, symbols)
 			}
 		}(i)
 	}
@@ -36,7 +44,7 @@ func main() {
 	log.Printf("Processing took %d milliseconds\n", t1-t0)
 }
 
-func simulateClient(clientID, numRequests int, side string) {
+func simulateClient(clientID, numRequests int, side pb.Side, symbols []string) {
 	conn, err := grpc.Dial("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
 	if err != nil {
 		log.Fatalf("Client %d did not connect: %v", clientID, err)
@@ -51,13 +59,26 @@ func simulateClient(clientID, numRequests int, side string) {
 	rand.Seed(time.Now().UnixNano()) // Seed for each client
 
 	for i := 0; i < numRequests; i++ {
-		orderType := "LIMIT"
+		orderType := pb.OrderType_ORDER_TYPE_LIMIT
 		amount := rand.Int31n(20) + 1 // Random amount between 1 and 20
 		price := rand.Int63n(500) + 1 // Random price between 1 and 500
 
+		userId, symbol := int32(clientID*numRequests+i), symbols[rand.Intn(len(symbols))]
+
+		// Every order is placed by a new user, who first deposits what the order reserves.
+		deposit := &pb.TransferRequest{UserId: userId, Asset: "CASH", Amount: int64(amount) * price}
+		if side == pb.Side_SIDE_SELL {
+			deposit = &pb.TransferRequest{UserId: userId, Asset: symbol, Amount: int64(amount)}
+		}
+		if _, err := c.Deposit(ctx, deposit); err != nil && status.Code(err) != codes.FailedPrecondition {
+			log.Printf("Client %d could not deposit: %v", clientID, err)
+			continue
+		}
+
 		_, err := c.SendOrder(ctx, &pb.OrderRequest{
-			UserId:    int32(clientID*numRequests + i),
-			Type:      side,
+			UserId:    userId,
+			Symbol:    symbol,
+			Side:      side,
 			OrderType: orderType,
 			Amount:    amount,
 			Price:     price,
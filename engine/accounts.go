@@ -0,0 +1,217 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MichalPitr/exchange/accounts"
+	"github.com/MichalPitr/exchange/orderbook"
+	pb "github.com/MichalPitr/exchange/protos"
+)
+
+// asset returns what orders of side reserve: cash for buy orders, the instrument of the market for sell orders.
+func asset(m *market, side orderbook.Side) string {
+	if side == orderbook.Buy {
+		return accounts.Cash
+	}
+	return m.symbol
+}
+
+// budgeted tells whether order spends a budget rather than trading up to its limit price. That's MARKET and STOP buy
+// orders, whose cost isn't known before they trade.
+func budgeted(order orderbook.Order) bool {
+	return order.Type == orderbook.Buy && (order.OrderType == orderbook.Market || order.OrderType == orderbook.Stop)
+}
+
+// required returns what an open order holds: the open amount of sell orders, the cost of the open amount of buy orders
+// at their limit price plus the highest fees it could pay, or what's left of the budget of MARKET and STOP buy orders.
+// Sellers pay their fees out of what they are paid.
+func required(m *market, order orderbook.Order) int64 {
+	open := int64(order.Amount + order.Hidden)
+	switch {
+	case order.Type == orderbook.Sell:
+		return open
+	case budgeted(order):
+		return order.Budget
+	}
+	cost := order.Price * open
+	return cost + m.fees.reserve(cost)
+}
+
+// budget returns what a MARKET or STOP buy order reserves. MARKET orders reserve what sweeping the sell book up to
+// their price limit costs right now, STOP orders the cost of their amount at the stop price plus their slippage
+// protection or the collar, both plus the highest fees that could cost. Orders stop trading once their budget is spent.
+// STOP orders that have neither are rejected once they are applied.
+func budget(m *market, order orderbook.Order) int64 {
+	if order.OrderType == orderbook.Stop {
+		ticks := max(int64(order.MaxSlippageTicks), m.collarTicks)
+		cost := int64(order.Amount) * (order.StopPrice + ticks*m.tickSize)
+		return cost + m.fees.reserve(cost)
+	}
+	limit, left, cost := priceLimit(m, order), int64(order.Amount), int64(0)
+	for _, level := range m.sellBook.Depth(0) {
+		if level.Price > limit || left == 0 {
+			break
+		}
+		amount := min(left, level.Amount)
+		cost += amount * level.Price
+		left -= amount
+	}
+	return cost + m.fees.reserve(cost)
+}
+
+// reserveFunds reserves what a new order, or an amendment, needs from the balance of its user as the command is
+// sequenced. Commands the balance can't cover are marked unfunded, which is journaled with them, so that replaying
+// them doesn't depend on what the other markets did in the meantime.
+func reserveFunds(m *market, cmd *command) {
+	switch cmd.kind {
+	case newOrder:
+		if budgeted(cmd.order) {
+			cmd.order.Budget = budget(m, cmd.order)
+		}
+		cmd.unfunded = !m.accounts.Reserve(cmd.order.Id, cmd.order.UserID, asset(m, cmd.order.Type), required(m, cmd.order), true)
+	case amendOrder:
+		_, order, err := findOwnOrder(m, cmd.orderId, cmd.userId)
+		if err != nil {
+			return
+		}
+		amended := *order
+		if cmd.price > 0 {
+			amended.Price = cmd.price
+		}
+		if cmd.amount > 0 {
+			amended.Amount, amended.Hidden = cmd.amount, 0
+		}
+		if extra := required(m, amended) - m.accounts.Held(order.Id); extra > 0 {
+			if m.accounts.Reserve(order.Id, order.UserID, asset(m, order.Type), extra, true) {
+				cmd.reserved = extra
+			} else {
+				cmd.unfunded = true
+			}
+		}
+	}
+}
+
+// fundsDependOn tells whether what cmd reserves depends on the commands sequenced before it but not applied yet. Any of
+// them can change the order an amendment is for, or the sell book that MARKET buys are budgeted against. Other new
+// orders reserve a fixed amount, which only commands of their own user can make unaffordable, as trades with other
+// users' orders only ever free funds.
+func fundsDependOn(pending []command, cmd command) bool {
+	if cmd.kind != newOrder && cmd.kind != amendOrder {
+		return false
+	}
+	for _, p := range pending {
+		if !p.kind.journaled() {
+			continue
+		}
+		if cmd.kind == amendOrder || budgeted(cmd.order) {
+			return true
+		}
+		owner := p.userId
+		if p.kind == newOrder {
+			owner = p.order.UserID
+		}
+		if p.kind != expireOrders && owner == cmd.order.UserID {
+			return true
+		}
+	}
+	return false
+}
+
+// reserve holds amount for order without checking the balance of its user, which was checked when the order was
+// first sequenced.
+func reserve(m *market, order orderbook.Order, amount int64) {
+	m.accounts.Reserve(order.Id, order.UserID, asset(m, order.Type), amount, false)
+}
+
+// updateHold adjusts what an order holds to its state in a report. Orders hold nothing once they are done.
+func updateHold(m *market, order orderbook.Order, execType pb.ExecType) {
+	if m.accounts == nil {
+		return
+	}
+	switch execType {
+	case pb.ExecType_EXEC_TYPE_FILL, pb.ExecType_EXEC_TYPE_CANCELLED, pb.ExecType_EXEC_TYPE_REJECTED, pb.ExecType_EXEC_TYPE_EXPIRED:
+		m.accounts.Hold(order.Id, 0)
+	default:
+		m.accounts.Hold(order.Id, required(m, order))
+	}
+}
+
+// refreshHold adjusts what an order holds to its state in the books, after an amendment failed.
+func refreshHold(m *market, orderId uint64) {
+	for _, book := range []*orderbook.Book{m.buyBook, m.sellBook} {
+		if order, ok := book.Get(orderId); ok {
+			m.accounts.Hold(orderId, required(m, *order))
+			return
+		}
+	}
+	m.accounts.Hold(orderId, 0)
+}
+
+func rejectUnfunded(m *market, order orderbook.Order) result {
+	message := fmt.Sprintf("Insufficient %s to reserve %d", asset(m, order.Type), required(m, order))
+	m.report(order, pb.ExecType_EXEC_TYPE_REJECTED, message)
+	return result{
+		message:      message,
+		status:       pb.OrderStatus_ORDER_STATUS_REJECTED,
+		rejectReason: pb.RejectReason_REJECT_REASON_INSUFFICIENT_FUNDS,
+		order:        order,
+	}
+}
+
+func (e *Engine) Deposit(ctx context.Context, in *pb.TransferRequest) (*pb.AccountInfo, error) {
+	if err := e.validateTransfer(in); err != nil {
+		return nil, err
+	}
+	if err := e.accounts.Deposit(in.UserId, in.Asset, in.Amount); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return e.accountInfo(in.UserId), nil
+}
+
+func (e *Engine) Withdraw(ctx context.Context, in *pb.TransferRequest) (*pb.AccountInfo, error) {
+	if err := e.validateTransfer(in); err != nil {
+		return nil, err
+	}
+	err := e.accounts.Withdraw(in.UserId, in.Asset, in.Amount)
+	if errors.Is(err, accounts.ErrInsufficientFunds) {
+		return nil, failedPrecondition(pb.RejectReason_REJECT_REASON_INSUFFICIENT_FUNDS, "amount", "Can't withdraw %d %s: %v", in.Amount, in.Asset, err)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return e.accountInfo(in.UserId), nil
+}
+
+func (e *Engine) GetAccount(ctx context.Context, in *pb.GetAccountRequest) (*pb.AccountInfo, error) {
+	if e.accounts == nil {
+		return nil, status.Error(codes.FailedPrecondition, "Accounts are disabled")
+	}
+	return e.accountInfo(in.UserId), nil
+}
+
+func (e *Engine) validateTransfer(in *pb.TransferRequest) error {
+	if e.accounts == nil {
+		return status.Error(codes.FailedPrecondition, "Accounts are disabled")
+	}
+	if _, ok := e.markets[in.Asset]; !ok && in.Asset != accounts.Cash {
+		return invalidArgument(pb.RejectReason_REJECT_REASON_UNKNOWN_SYMBOL, "asset", "Asset %q is neither %s nor a known symbol", in.Asset, accounts.Cash)
+	}
+	if in.Amount <= 0 {
+		return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_AMOUNT, "amount", "Amount %d is not positive", in.Amount)
+	}
+	return nil
+}
+
+func (e *Engine) accountInfo(userId int32) *pb.AccountInfo {
+	info := &pb.AccountInfo{UserId: userId}
+	assets, balances := e.accounts.Balances(userId)
+	for i, b := range balances {
+		info.Balances = append(info.Balances, &pb.Balance{Asset: assets[i], Total: b.Total, Reserved: b.Reserved, Available: b.Available()})
+	}
+	return info
+}
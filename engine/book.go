@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MichalPitr/exchange/orderbook"
+	pb "github.com/MichalPitr/exchange/protos"
+)
+
+func (e *Engine) GetOrderBook(ctx context.Context, in *pb.OrderBookRequest) (*pb.OrderBookResponse, error) {
+	if in.Depth < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Depth %d is negative", in.Depth)
+	}
+	if _, ok := pb.BookLevel_name[int32(in.Level)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "Book level %v is unknown", in.Level)
+	}
+
+	// The query is sequenced like any other command, so it never sees a book in the middle of matching.
+	result, err := e.submit(ctx, command{kind: queryOrderBook, symbol: in.Symbol, depth: int(in.Depth), bookLevel: in.Level})
+	if err != nil {
+		return nil, err
+	}
+	if !result.success {
+		return nil, status.Error(codes.NotFound, result.message)
+	}
+	return result.orderBook, nil
+}
+
+// processBookQuery captures the books as they are after the last applied command.
+func processBookQuery(m *market, depth int, level pb.BookLevel) result {
+	return result{success: true, orderBook: &pb.OrderBookResponse{
+		Symbol: m.symbol,
+		Seq:    m.seq,
+		Bids:   bookLevels(m.buyBook, depth, level),
+		Asks:   bookLevels(m.sellBook, depth, level),
+	}}
+}
+
+func bookLevels(book *orderbook.Book, depth int, detail pb.BookLevel) []*pb.PriceLevel {
+	if detail != pb.BookLevel_BOOK_LEVEL_L3 {
+		return priceLevels(book.Depth(depth))
+	}
+
+	var levels []*pb.PriceLevel
+	book.Each(func(level orderbook.Level, orders []orderbook.Order) bool {
+		priced := priceLevel(level)
+		for _, order := range orders {
+			priced.Orders = append(priced.Orders, &pb.RestingOrder{OrderId: order.Id, Amount: order.Amount, Time: order.Time})
+		}
+		levels = append(levels, priced)
+		return depth <= 0 || len(levels) < depth
+	})
+	return levels
+}
@@ -1,13 +1,20 @@
 package engine
 
 import (
-	"container/heap"
 	"context"
 	"fmt"
 	"log"
+	"math"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"google.golang.org/grpc/status"
+
+	"github.com/MichalPitr/exchange/accounts"
+	"github.com/MichalPitr/exchange/instrument"
+	"github.com/MichalPitr/exchange/journal"
 	"github.com/MichalPitr/exchange/orderbook"
 	"github.com/MichalPitr/exchange/reporter"
 
@@ -16,152 +23,1008 @@ import (
 
 type Engine struct {
 	pb.UnimplementedOrderServiceServer
-	orderQueue chan orderbook.Order
+	instruments *instrument.Registry
+	markets     map[string]*market
+	processing  sync.WaitGroup
+
+	executions         *executionHub
+	subscriptionsEnded chan struct{}
+	endSubscriptions   sync.Once
+
+	nextOrderId atomic.Uint64 // Order ids are unique across all markets.
+	accounts    *accounts.Accounts
+}
+
+// market is the shard of the engine that trades a single instrument. Commands for the instrument are sequenced through
+// its orderQueue and applied by a dedicated goroutine, so markets match in parallel while each sees a strict order.
+type market struct {
+	symbol     string
+	orderQueue chan command
 	sellBook   *orderbook.Book
 	buyBook    *orderbook.Book
-	reporter   *reporter.Reporter
+	reporter   reporter.Sink
+	journal    *journal.Journal
+	dataDir    string
+	feeds      []*feed // One for every depth subscribers follow.
+	executions *executionHub
+	reports    []*pb.ExecutionReport // Execution reports of the command being applied.
+	orders     *orderRegistry
+	accounts   *accounts.Market // Nil if balances aren't checked.
+	fees       *feeSchedule
+
+	// Number of reports the market sent every user, which the sequence numbers of the users continue from after a
+	// restart.
+	reportCounts map[int32]uint64
+
+	tickSize    int64
+	collarTicks int64 // MARKET orders don't trade further than this from lastTrade, 0 disables the collar.
+	lastTrade   int64 // Price of the last trade, 0 before the first one.
+	lastTradeId uint64
+	records     uint64 // Number of the last record handed to the reporter, counted from 1 like durable sinks do.
+	written     uint64 // Number of the last record the reporter held when the market was opened.
+
+	sessionClose time.Duration // Time of day, in UTC, at which DAY orders expire.
+	expiries     expiryQueue
+	buyStops     *stopBook
+	sellStops    *stopBook
 
-	mutex       sync.Mutex
-	nextOrderId uint64
+	seq         uint64 // Sequence number of the last command applied to the books.
+	lastTime    int64  // Last timestamp handed out by now.
+	commandTime int64  // Timestamp of the command being applied.
+	replaying   bool   // Set while recovering from the journal, everything it would report was reported before.
+
+	snapshotEvery    uint64
+	snapshotInterval time.Duration
+	snapshotSeq      uint64        // Sequence number covered by the last snapshot.
+	snapshotDone     chan struct{} // Closed once the snapshot being written in the background is done.
+}
+
+type Config struct {
+	QueueSize        int // Capacity of each market's command queue.
+	Instruments      *instrument.Registry
+	DataDir          string        // Journals, snapshots and trade logs are kept here.
+	SnapshotEvery    uint64        // Snapshot a market after this many commands, 0 disables.
+	SnapshotInterval time.Duration // Snapshot markets that changed this often, 0 disables.
+	SessionClose     time.Duration // Time of day, in UTC, at which the trading session closes and DAY orders expire.
+	// Accounts are checked before orders are accepted, and settled as they trade. Nil lets anyone trade any amount.
+	Accounts *accounts.Accounts
+	// TradeSink opens where the trades of a market go. Nil writes a CSV trade log per market into DataDir, fsynced with
+	// every batch.
+	TradeSink func(symbol string) (reporter.Sink, error)
 }
 
+// Match is a trade between an incoming order, the taker, and a resting one, the maker.
 type Match struct {
-	buyId  uint64
-	sellId uint64
-	amount int32
-	price  int64 // Technically unnecessary as it can be reconstructed from the orders and picking whichever was older but convenient.
+	tradeId    uint64 // Increases by one with every trade of the market.
+	time       int64  // Timestamp of the command that traded.
+	symbol     string
+	aggressor  orderbook.Side // Side of the taker.
+	buyId      uint64
+	sellId     uint64
+	buyUserId  int32
+	sellUserId int32
+	amount     int32
+	price      int64 // Price of the maker.
+	makerFee   int64 // Negative for a rebate.
+	takerFee   int64
 }
 
-func (m Match) csvFormat() string {
-	return fmt.Sprintf("%d,%d,%d,%d", m.buyId, m.sellId, m.amount, m.price)
+// fees returns what the buyer and the seller of m pay in fees.
+func (m Match) fees() (buyer, seller int64) {
+	if m.aggressor == orderbook.Buy {
+		return m.takerFee, m.makerFee
+	}
+	return m.makerFee, m.takerFee
 }
 
-func New(queueSize int) (*Engine, error) {
-	reporter, err := reporter.New("trades.log")
-	if err != nil {
+func (m Match) record() reporter.Trade {
+	trade := reporter.Trade{TradeId: m.tradeId, Time: m.time, Symbol: m.symbol, AggressorSide: string(m.aggressor), Amount: m.amount, Price: m.price,
+		MakerFee: m.makerFee, TakerFee: m.takerFee}
+	if m.aggressor == orderbook.Buy {
+		trade.TakerOrderId, trade.TakerUserId, trade.MakerOrderId, trade.MakerUserId = m.buyId, m.buyUserId, m.sellId, m.sellUserId
+	} else {
+		trade.TakerOrderId, trade.TakerUserId, trade.MakerOrderId, trade.MakerUserId = m.sellId, m.sellUserId, m.buyId, m.buyUserId
+	}
+	return trade
+}
+
+// writeTrade hands trade to the reporter. While replaying, only the records that the reporter doesn't hold are written.
+func (m *market) writeTrade(trade reporter.Trade) {
+	m.records++
+	if !m.replaying || m.records > m.written {
+		m.reporter.WriteTrade(trade)
+	}
+}
+
+// writeCancel hands cancel to the reporter like writeTrade.
+func (m *market) writeCancel(cancel reporter.Cancel) {
+	m.records++
+	if !m.replaying || m.records > m.written {
+		m.reporter.WriteCancel(cancel)
+	}
+}
+
+// cancelRecord tells the reporter that what was left of order was removed from the book.
+func cancelRecord(symbol string, order orderbook.Order) reporter.Cancel {
+	return reporter.Cancel{Symbol: symbol, OrderId: order.Id, UserId: order.UserID, Amount: order.Amount + order.Hidden, Price: order.Price}
+}
+
+type commandType int
+
+const (
+	newOrder commandType = iota
+	cancelOrder
+	amendOrder
+	subscribeMarketData
+	expireOrders
+	queryOrderBook
+)
+
+// journaled tells whether commands of this type change the books, and so have to be journaled.
+func (t commandType) journaled() bool {
+	return t != subscribeMarketData && t != queryOrderBook
+}
+
+// command is a request sequenced through the orderQueue, so that it's applied to the books in arrival order.
+type command struct {
+	kind       commandType
+	symbol     string
+	seq        uint64          // Assigned by the market when the command is sequenced.
+	time       int64           // Assigned by the market when the command is sequenced.
+	order      orderbook.Order // Set for newOrder.
+	orderId    uint64          // Set for cancelOrder and amendOrder.
+	userId     int32           // Set for cancelOrder and amendOrder.
+	price      int64           // Set for amendOrder, 0 keeps the current price.
+	amount     int32           // Set for amendOrder, 0 keeps the current amount.
+	subscriber *subscriber     // Set for subscribeMarketData.
+	depth      int             // Set for queryOrderBook, 0 for all levels.
+	bookLevel  pb.BookLevel    // Set for queryOrderBook.
+	unfunded   bool            // Set for newOrder and amendOrder that the user's balance couldn't cover.
+	reserved   int64           // Set for amendOrder, what the market reserved on top of what the order held.
+	resultChan chan result     // Nil for commands that the market issues itself.
+}
+
+// result is handed back to the RPC handler once its command has been processed.
+type result struct {
+	success      bool
+	message      string
+	status       pb.OrderStatus
+	rejectReason pb.RejectReason
+	order        orderbook.Order // State of the order after the command, Amount is the unfilled remainder.
+	keptPriority bool
+	repriced     bool // Post-only order was repriced to not take liquidity.
+	matches      []Match
+	triggered    []result // Stop orders released by the trades of the command, in the order they were executed.
+	orderBook    *pb.OrderBookResponse
+}
+
+// New creates an engine with one market per instrument. The state of each market is recovered from the snapshots and
+// journal in cfg.DataDir, if there are any.
+func New(cfg Config) (*Engine, error) {
+	e := &Engine{
+		instruments:        cfg.Instruments,
+		markets:            make(map[string]*market),
+		executions:         newExecutionHub(),
+		subscriptionsEnded: make(chan struct{}),
+		accounts:           cfg.Accounts,
+	}
+	for _, inst := range cfg.Instruments.Instruments() {
+		m, err := openMarket(e, inst, cfg)
+		if err != nil {
+			e.closeFiles()
+			return nil, err
+		}
+		e.markets[inst.Symbol] = m
+		e.executions.restore(m.reportCounts)
+	}
+	return e, nil
+}
+
+func openMarket(e *Engine, inst instrument.Instrument, cfg Config) (*market, error) {
+	symbol := inst.Symbol
+	m := &market{
+		symbol:           symbol,
+		tickSize:         inst.TickSize,
+		collarTicks:      inst.MarketCollarTicks,
+		fees:             newFeeSchedule(inst.Fees),
+		orderQueue:       make(chan command, cfg.QueueSize),
+		sellBook:         orderbook.New(true),
+		buyBook:          orderbook.New(false),
+		buyStops:         newStopBook(orderbook.Buy),
+		sellStops:        newStopBook(orderbook.Sell),
+		dataDir:          cfg.DataDir,
+		executions:       e.executions,
+		orders:           newOrderRegistry(),
+		reportCounts:     make(map[int32]uint64),
+		snapshotEvery:    cfg.SnapshotEvery,
+		snapshotInterval: cfg.SnapshotInterval,
+		sessionClose:     cfg.SessionClose,
+	}
+	if cfg.Accounts != nil {
+		m.accounts = cfg.Accounts.Market(symbol)
+	}
+
+	var err error
+	openSink := cfg.TradeSink
+	if openSink == nil {
+		openSink = func(symbol string) (reporter.Sink, error) {
+			return reporter.Open(reporter.CSV, filepath.Join(cfg.DataDir, "trades_"+symbol), reporter.Options{})
+		}
+	}
+	if m.reporter, err = openSink(symbol); err != nil {
 		return nil, err
 	}
-	return &Engine{
-		orderQueue:  make(chan orderbook.Order, queueSize),
-		sellBook:    orderbook.New(true),
-		buyBook:     orderbook.New(false),
-		nextOrderId: 0,
-		reporter:    reporter,
-	}, nil
+	// Replaying writes the records that a durable reporter lost again, sinks that aren't durable get none of them.
+	m.written = math.MaxUint64
+	if durable, ok := m.reporter.(reporter.Durable); ok {
+		m.written = durable.Written()
+	}
+	if err := recoverMarket(e, m); err != nil {
+		m.reporter.Close()
+		return nil, fmt.Errorf("recovering %s: %w", symbol, err)
+	}
+	if m.written != math.MaxUint64 {
+		m.records = max(m.records, m.written)
+	}
+	if err := m.reporter.Flush(); err != nil {
+		m.journal.Close()
+		m.reporter.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+func (e *Engine) closeFiles() {
+	for _, m := range e.markets {
+		m.journal.Close()
+		m.reporter.Close()
+	}
 }
 
 func (e *Engine) PrintOrderbookStats() {
-	topBuy, _ := e.buyBook.Peek()
-	topSell, _ := e.sellBook.Peek()
-	log.Printf("Buy book size: %d, Top buy: %d\n", e.buyBook.Len(), topBuy.Price)
-	log.Printf("Sell book size: %d, Top sell: %d\n", e.sellBook.Len(), topSell.Price)
+	for _, inst := range e.instruments.Instruments() {
+		m := e.markets[inst.Symbol]
+		topBuy, _ := m.buyBook.Peek()
+		topSell, _ := m.sellBook.Peek()
+		log.Printf("%s buy book size: %d, Top buy: %d\n", m.symbol, m.buyBook.Len(), topBuy.Price)
+		log.Printf("%s sell book size: %d, Top sell: %d\n", m.symbol, m.sellBook.Len(), topSell.Price)
+	}
 }
 
+// Close stops the markets once they have drained their queues. No orders may be submitted afterwards.
 func (e *Engine) Close() {
+	for _, m := range e.markets {
+		close(m.orderQueue)
+	}
+	e.processing.Wait()
+
 	e.PrintOrderbookStats()
-	e.reporter.Close()
+	e.closeFiles()
 }
 
 func (e *Engine) SendOrder(ctx context.Context, in *pb.OrderRequest) (*pb.OrderResponse, error) {
 	// log.Printf("Received: %v", in)
-	resultChan := make(chan orderbook.OrderResult, 1)
-
-	e.mutex.Lock()
-	// Process the order here
+	if err := validateOrder(in, time.Now()); err != nil {
+		return nil, err
+	}
+	// Time is stamped by the market when the order is sequenced.
 	order := orderbook.Order{
-		Id:         e.nextOrderId,
-		UserID:     in.UserId,
-		Type:       in.Type,
-		OrderType:  in.OrderType,
-		Amount:     in.Amount,
-		Price:      in.Price,
-		Time:       time.Now().UnixNano(),
-		ResultChan: resultChan,
+		Id:        e.nextOrderId.Add(1) - 1,
+		UserID:    in.UserId,
+		Symbol:    in.Symbol,
+		Type:      sideFromProto(in.Side),
+		OrderType: orderTypeFromProto(in.OrderType),
+		Amount:    in.Amount,
+		Price:     in.Price,
+
+		MaxSlippageTicks: in.MaxSlippageTicks,
+		TimeInForce:      timeInForceFromProto(in.TimeInForce, orderTypeFromProto(in.OrderType)),
+		ExpireTime:       in.ExpireTime,
+		StopPrice:        in.StopPrice,
+		DisplayAmount:    in.DisplayAmount,
+		PostOnly:         postOnlyFromProto(in.PostOnly),
+
+		SelfTradePrevention: selfTradePreventionFromProto(in.SelfTradePrevention),
+		SelfTradeGroup:      in.SelfTradeGroup,
+	}
+	if order.OrderType == orderbook.Market || order.OrderType == orderbook.Stop {
+		order.Price = 0 // Market orders trade at any price, up to their protection limit.
+	}
+
+	result, err := e.submit(ctx, command{kind: newOrder, symbol: in.Symbol, order: order})
+	if err != nil {
+		return nil, err
+	}
+
+	executed, averagePrice := executedVolume(result.matches)
+	response := &pb.OrderResponse{
+		Status:         "Success",
+		OrderId:        order.Id,
+		OrderStatus:    result.status,
+		ExecutedAmount: executed,
+		AveragePrice:   averagePrice,
+		Fills:          fills(result.matches),
+		Price:          result.order.Price,
+		Repriced:       result.repriced,
+	}
+	switch result.status {
+	case pb.OrderStatus_ORDER_STATUS_REJECTED:
+		response.Status = "Rejected"
+		response.Details = result.message
+		response.RejectReason = result.rejectReason
+	case pb.OrderStatus_ORDER_STATUS_FILLED:
+		response.Details = fmt.Sprintf("Order %d filled", order.Id)
+	case pb.OrderStatus_ORDER_STATUS_PARTIALLY_FILLED:
+		response.Details = fmt.Sprintf("Order %d partially filled, %d resting", order.Id, result.order.Amount)
+	case pb.OrderStatus_ORDER_STATUS_RESTING:
+		response.Details = fmt.Sprintf("Order %d resting", order.Id)
+		if result.repriced {
+			response.Details = fmt.Sprintf("Order %d repriced to %d and resting", order.Id, result.order.Price)
+		}
+	case pb.OrderStatus_ORDER_STATUS_PENDING_TRIGGER:
+		response.Details = fmt.Sprintf("Order %d waiting for a trade at %d", order.Id, order.StopPrice)
+	case pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED:
+		response.UnfilledAmount = result.order.Amount
+		response.Details = fmt.Sprintf("Order %d executed %d, remaining %d cancelled: %s", order.Id, executed, result.order.Amount, result.message)
+	}
+	return response, nil
+}
+
+func (e *Engine) CancelOrder(ctx context.Context, in *pb.CancelRequest) (*pb.CancelResponse, error) {
+	result, err := e.submit(ctx, command{kind: cancelOrder, symbol: in.Symbol, orderId: in.OrderId, userId: in.UserId})
+	if err != nil {
+		return nil, err
 	}
-	e.nextOrderId++
-	e.mutex.Unlock()
+	if !result.success {
+		return &pb.CancelResponse{Status: "Rejected", Details: result.message}, nil
+	}
+	return &pb.CancelResponse{Status: "Success", Details: result.message}, nil
+}
 
-	// Enqueue the order.
-	e.orderQueue <- order
+func (e *Engine) AmendOrder(ctx context.Context, in *pb.AmendRequest) (*pb.AmendResponse, error) {
+	result, err := e.submit(ctx, command{kind: amendOrder, symbol: in.Symbol, orderId: in.OrderId, userId: in.UserId, price: in.Price, amount: in.Amount})
+	if err != nil {
+		return nil, err
+	}
+	if !result.success {
+		return &pb.AmendResponse{Status: "Rejected", Details: result.message}, nil
+	}
+	return &pb.AmendResponse{
+		Status:       "Success",
+		Details:      result.message,
+		Price:        result.order.Price,
+		Amount:       result.order.Amount,
+		KeptPriority: result.keptPriority,
+		Fills:        fills(result.matches),
+	}, nil
+}
+
+// submit sequences cmd through the orderQueue of its market and waits until it's processed. Gives up once ctx is
+// done, note that a command which already made it into the queue is still processed.
+func (e *Engine) submit(ctx context.Context, cmd command) (result, error) {
+	m, ok := e.markets[cmd.symbol]
+	if !ok {
+		return result{
+			message:      fmt.Sprintf("Unknown symbol %q", cmd.symbol),
+			status:       pb.OrderStatus_ORDER_STATUS_REJECTED,
+			rejectReason: pb.RejectReason_REJECT_REASON_UNKNOWN_SYMBOL,
+		}, nil
+	}
+
+	cmd.resultChan = make(chan result, 1)
+	select {
+	case m.orderQueue <- cmd:
+	case <-ctx.Done():
+		return result{}, status.FromContextError(ctx.Err()).Err()
+	}
+
+	select {
+	case result := <-cmd.resultChan:
+		return result, nil
+	case <-ctx.Done():
+		return result{}, status.FromContextError(ctx.Err()).Err()
+	}
+}
 
-	log.Printf("Order queue size: %d\n", len(e.orderQueue))
+func executedVolume(matches []Match) (int32, float64) {
+	var amount int32
+	var notional int64
+	for _, m := range matches {
+		amount += m.amount
+		notional += int64(m.amount) * m.price
+	}
+	if amount == 0 {
+		return 0, 0
+	}
+	return amount, float64(notional) / float64(amount)
+}
 
-	return &pb.OrderResponse{Status: "Success", Details: fmt.Sprintf("Order %d is getting processed", order.Id)}, nil
+func fills(matches []Match) []*pb.Fill {
+	fills := make([]*pb.Fill, 0, len(matches))
+	for _, m := range matches {
+		fills = append(fills, &pb.Fill{TradeId: m.tradeId, BuyOrderId: m.buyId, SellOrderId: m.sellId, Amount: m.amount, Price: m.price, Fee: m.takerFee})
+	}
+	return fills
 }
 
+// ProcessOrders runs the matching loop of every market until the engine is closed.
 func ProcessOrders(e *Engine) {
-	for order := range e.orderQueue {
-		processOrder(e, order)
-		order.ResultChan <- orderbook.OrderResult{Message: "Processed", Success: true}
+	for _, m := range e.markets {
+		e.processing.Add(1)
+		go func(m *market) {
+			defer e.processing.Done()
+			processCommands(e, m)
+		}(m)
+	}
+	e.processing.Wait()
+}
+
+// Upper bound on commands journaled with a single fsync.
+const maxBatchSize = 256
+
+func processCommands(e *Engine, m *market) {
+	var snapshotTicks <-chan time.Time
+	if m.snapshotInterval > 0 {
+		ticker := time.NewTicker(m.snapshotInterval)
+		defer ticker.Stop()
+		snapshotTicks = ticker.C
+	}
+	expiryTicker := time.NewTicker(expiryCheckInterval)
+	defer expiryTicker.Stop()
+
+	batch := make([]command, 0, maxBatchSize)
+	for {
+		select {
+		case cmd, ok := <-m.orderQueue:
+			if !ok {
+				waitForSnapshot(m)
+				return
+			}
+			batch = append(batch[:0], cmd)
+			processBatch(m, drain(m, batch))
+			if m.snapshotEvery > 0 && m.seq-m.snapshotSeq >= m.snapshotEvery {
+				takeSnapshot(e, m)
+			}
+		case <-snapshotTicks:
+			if m.seq > m.snapshotSeq {
+				takeSnapshot(e, m)
+			}
+		case now := <-expiryTicker.C:
+			// Expiring is sequenced like any other command, so that replaying the journal expires the same orders.
+			if m.expiries.due(now.UnixNano()) {
+				processBatch(m, []command{{kind: expireOrders, symbol: m.symbol}})
+			}
+		}
+	}
+}
+
+// drain adds whatever else is already queued to the batch, so that a single fsync covers all of it.
+func drain(m *market, batch []command) []command {
+	for len(batch) < maxBatchSize {
+		select {
+		case cmd, ok := <-m.orderQueue:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, cmd)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+func processBatch(m *market, batch []command) {
+	start, journaled := 0, 0
+	for i := range batch {
+		if !batch[i].kind.journaled() {
+			continue
+		}
+		// Funds are reserved against the books and balances as they are when the command is applied.
+		if m.accounts != nil && fundsDependOn(batch[start:i], batch[i]) {
+			commit(m, batch[start:i], journaled)
+			start, journaled = i, 0
+		}
+		m.seq++
+		batch[i].seq = m.seq
+		batch[i].time = m.now()
+		if batch[i].kind == newOrder && batch[i].order.TimeInForce == orderbook.Day {
+			batch[i].order.ExpireTime = m.sessionCloseAfter(batch[i].time)
+		}
+		if m.accounts != nil {
+			reserveFunds(m, &batch[i])
+		}
+		if err := appendToJournal(m.journal, batch[i]); err != nil {
+			log.Fatalf("Failed to journal %s command %d: %v", m.symbol, batch[i].seq, err)
+		}
+		journaled++
 	}
+	commit(m, batch[start:], journaled)
 }
 
-func processOrder(e *Engine, order orderbook.Order) {
-	log.Printf("Processing order: %v\n", order)
-	remainder, matches := match(e, order)
-	if remainder == 0 {
+// commit applies the commands of batch, and hands back their results, once the journaled ones are durable.
+func commit(m *market, batch []command, journaled int) {
+	// Nothing is applied, and so acknowledged, before it's durable.
+	if journaled > 0 {
+		if err := m.journal.Sync(); err != nil {
+			log.Fatalf("Failed to sync %s journal: %v", m.symbol, err)
+		}
+	}
+	results := make([]result, len(batch))
+	for i, cmd := range batch {
+		results[i] = apply(m, cmd)
+		publishMarketData(m, results[i])
+		publishExecutions(m)
+	}
+	// Nor are trades acknowledged before their settlement is.
+	if m.accounts != nil {
+		if err := m.accounts.Sync(); err != nil {
+			log.Fatalf("Failed to sync the ledger after %s command %d: %v", m.symbol, m.seq, err)
+		}
+	}
+	for i, cmd := range batch {
+		if cmd.resultChan != nil {
+			cmd.resultChan <- results[i]
+		}
+	}
+}
+
+// apply runs a sequenced command against the books of its market. Orders that expired by the time of the command are
+// removed first, so that it can't trade with them while they wait for the next expiry check.
+func apply(m *market, cmd command) result {
+	m.commandTime = cmd.time
+	if cmd.kind.journaled() && cmd.kind != expireOrders && m.expiries.due(cmd.time) {
+		processExpire(m, cmd.time)
+	}
+	switch cmd.kind {
+	case newOrder:
+		cmd.order.Time = cmd.time
+		if cmd.unfunded {
+			return rejectUnfunded(m, cmd.order)
+		}
+		if m.replaying && m.accounts != nil {
+			reserve(m, cmd.order, required(m, cmd.order))
+		}
+		result := processOrder(m, cmd.order)
+		result.triggered = releaseStops(m)
+		return result
+	case cancelOrder:
+		return processCancel(m, cmd.orderId, cmd.userId)
+	case amendOrder:
+		if cmd.unfunded {
+			return result{message: fmt.Sprintf("Insufficient funds to amend order %d", cmd.orderId)}
+		}
+		if m.replaying && cmd.reserved > 0 {
+			if _, order, err := findOwnOrder(m, cmd.orderId, cmd.userId); err == nil {
+				reserve(m, *order, cmd.reserved)
+			}
+		}
+		result := processAmend(m, cmd.orderId, cmd.userId, cmd.price, cmd.amount, cmd.time)
+		if !result.success && m.accounts != nil {
+			refreshHold(m, cmd.orderId)
+		}
+		result.triggered = releaseStops(m)
+		return result
+	case subscribeMarketData:
+		return processSubscribe(m, cmd.subscriber)
+	case expireOrders:
+		return processExpire(m, cmd.time)
+	case queryOrderBook:
+		return processBookQuery(m, cmd.depth, cmd.bookLevel)
+	}
+	return result{message: fmt.Sprintf("Unknown command %d", cmd.kind)}
+}
+
+// now returns a timestamp that is strictly increasing within the market, so time priority follows sequencing order.
+func (m *market) now() int64 {
+	t := time.Now().UnixNano()
+	if t <= m.lastTime {
+		t = m.lastTime + 1
+	}
+	m.lastTime = t
+	return t
+}
+
+func processOrder(m *market, order orderbook.Order) result {
+	log.Printf("Processing order %s#%d: %v\n", m.symbol, m.seq, order)
+	supported := order.OrderType == orderbook.Market || order.OrderType == orderbook.Limit ||
+		order.OrderType == orderbook.Stop || order.OrderType == orderbook.StopLimit
+	if (order.Type != orderbook.Buy && order.Type != orderbook.Sell) || !supported {
+		message := fmt.Sprintf("Unsupported order %s %s", order.Type, order.OrderType)
+		m.report(order, pb.ExecType_EXEC_TYPE_REJECTED, message)
+		return result{
+			message:      message,
+			status:       pb.OrderStatus_ORDER_STATUS_REJECTED,
+			rejectReason: pb.RejectReason_REJECT_REASON_INVALID_ORDER_TYPE,
+			order:        order,
+		}
+	}
+	if m.accounts != nil && budgeted(order) && order.OrderType == orderbook.Stop && order.MaxSlippageTicks == 0 && m.collarTicks == 0 {
+		// Without a protection limit the budget can't cover the prices a triggered stop order trades at.
+		message := fmt.Sprintf("STOP buy orders need slippage protection in %s, which has no collar", m.symbol)
+		m.report(order, pb.ExecType_EXEC_TYPE_REJECTED, message)
+		return result{
+			message:      message,
+			status:       pb.OrderStatus_ORDER_STATUS_REJECTED,
+			rejectReason: pb.RejectReason_REJECT_REASON_INVALID_SLIPPAGE,
+			order:        order,
+		}
+	}
+	repriced := false
+	if order.PostOnly != "" {
+		if price, crosses := postOnlyPrice(m, order); crosses {
+			if order.PostOnly == orderbook.PostOnlyReject || price <= 0 {
+				message := fmt.Sprintf("Post-only order would take liquidity at %d", order.Price)
+				m.report(order, pb.ExecType_EXEC_TYPE_REJECTED, message)
+				return result{
+					message:      message,
+					status:       pb.OrderStatus_ORDER_STATUS_REJECTED,
+					rejectReason: pb.RejectReason_REJECT_REASON_WOULD_TAKE_LIQUIDITY,
+					order:        order,
+				}
+			}
+			order.Price = price
+			repriced = true
+		}
+	}
+	m.report(order, pb.ExecType_EXEC_TYPE_NEW, "")
+	if order.OrderType == orderbook.Stop || order.OrderType == orderbook.StopLimit {
+		if reached(order, m.lastTrade) {
+			return trigger(m, order)
+		}
+		m.stopBook(order.Type).add(order)
+		return result{success: true, status: pb.OrderStatus_ORDER_STATUS_PENDING_TRIGGER, order: order}
+	}
+	result := executeOrder(m, order)
+	result.repriced = repriced
+	return result
+}
+
+// executeOrder matches an accepted order and rests whatever is left of a limit order.
+func executeOrder(m *market, order orderbook.Order) result {
+	if order.TimeInForce == orderbook.FOK && !fillable(m, order) {
+		message := "fill or kill order can't be filled completely"
+		m.report(order, pb.ExecType_EXEC_TYPE_CANCELLED, message)
+		return result{success: true, message: message, status: pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED, order: order}
+	}
+
+	// Post-only orders are priced to not cross, so they go straight to the book.
+	remainder, matches, prevented := order.Amount, []Match(nil), ""
+	if order.PostOnly == "" {
+		remainder, matches, prevented = match(m, order)
+	}
+	if order.TimeInForce == orderbook.FOK && remainder > 0 {
+		// Their trades are already settled, so a fill or kill order that wasn't filled completely can't be undone.
+		log.Fatalf("Fill or kill order %s#%d that was found fillable left %d unfilled: %s", m.symbol, order.Id, remainder, prevented)
+	}
+	if len(matches) > 0 {
+		m.lastTrade = matches[len(matches)-1].price
+	}
+	var message string
+	for _, match := range matches {
+		order.Filled += match.amount
+	}
+	taker := order
+	taker.Amount = remainder
+	status := pb.OrderStatus_ORDER_STATUS_FILLED
+	if prevented != "" {
+		status = pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED
+		message = prevented
+		m.report(taker, pb.ExecType_EXEC_TYPE_CANCELLED, message)
+	} else if remainder == 0 {
 		log.Printf("Fully matched order with: %v", matches)
 	} else {
 		if len(matches) > 0 {
 			fmt.Printf("Partially matched ordered with: %v\n", matches)
 		}
 
-		if order.OrderType == "MARKET" {
+		if order.OrderType == orderbook.Market {
 			// Unfilled part of market order does not enter orderbook.
-		} else if order.OrderType == "LIMIT" {
-			order.Amount = remainder
-			if order.Type == "BUY" {
-				heap.Push(e.buyBook, orderbook.Item{Order: order})
-			} else {
-				heap.Push(e.sellBook, orderbook.Item{Order: order})
+			status = pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED
+			message = "no liquidity left"
+			if opposite := oppositeBook(m, order.Type); opposite.Len() > 0 {
+				message = "protection limit reached"
+			}
+			m.report(taker, pb.ExecType_EXEC_TYPE_CANCELLED, message)
+		} else if order.TimeInForce == orderbook.IOC {
+			status = pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED
+			message = "immediate or cancel"
+			m.report(taker, pb.ExecType_EXEC_TYPE_CANCELLED, message)
+		} else if order.ExpireTime != 0 && order.ExpireTime <= order.Time {
+			status = pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED
+			message = "expired"
+			m.report(taker, pb.ExecType_EXEC_TYPE_EXPIRED, message)
+		} else if order.OrderType == orderbook.Limit {
+			resting := order
+			resting.Amount = remainder
+			if order.DisplayAmount > 0 && remainder > order.DisplayAmount {
+				resting.Amount = order.DisplayAmount
+				resting.Hidden = remainder - order.DisplayAmount
+			}
+			rest(m, resting)
+			status = pb.OrderStatus_ORDER_STATUS_RESTING
+			if len(matches) > 0 {
+				status = pb.OrderStatus_ORDER_STATUS_PARTIALLY_FILLED
 			}
 		}
 	}
-	for _, m := range matches {
-		e.reporter.Println(m.csvFormat())
+	for _, match := range matches {
+		m.writeTrade(match.record())
+	}
+	if !m.replaying {
+		m.reporter.Flush()
+	}
+
+	order.Amount = remainder
+	return result{success: true, message: message, status: status, order: order, matches: matches}
+}
+
+// findOwnOrder looks up a resting order in either book and checks that it belongs to userId.
+func findOwnOrder(m *market, orderId uint64, userId int32) (*orderbook.Book, *orderbook.Order, error) {
+	book := m.buyBook
+	order, ok := book.Get(orderId)
+	if !ok {
+		book = m.sellBook
+		order, ok = book.Get(orderId)
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("order %d is not resting in the %s orderbook", orderId, m.symbol)
 	}
-	e.reporter.Flush()
+	if order.UserID != userId {
+		return nil, nil, fmt.Errorf("order %d does not belong to user %d", orderId, userId)
+	}
+	return book, order, nil
 }
 
-func match(e *Engine, order orderbook.Order) (int32, []Match) {
-	// Check if order can be served by existing orders in the orderbook. Might have to combine multiple existing orders together.
-	remainingAmount := order.Amount
-	matches := make([]Match, 0)
-	if order.Type == "BUY" {
-		for e.sellBook.Len() > 0 && remainingAmount > 0 {
-			if top, ok := e.sellBook.Peek(); ok {
-				if top.Price > order.Price {
-					return remainingAmount, matches
-				}
-				if top.Amount > remainingAmount {
-					matches = append(matches, Match{order.Id, top.Id, remainingAmount, top.Price})
-					// Top SELL is larger than remaining BUY, so update existing SELL.
-					top.Amount -= remainingAmount
-					return 0, matches
-				} else {
-					matches = append(matches, Match{order.Id, top.Id, top.Amount, top.Price})
-					remainingAmount -= top.Amount
-					heap.Pop(e.sellBook)
-				}
+func processCancel(m *market, orderId uint64, userId int32) result {
+	book, _, err := findOwnOrder(m, orderId, userId)
+	if err != nil {
+		if result, ok := cancelStop(m, orderId, userId); ok {
+			return result
+		}
+		return result{message: err.Error()}
+	}
+
+	cancelled := cancelResting(m, book, orderId, "Cancelled by user")
+	if !m.replaying {
+		m.reporter.Flush()
+	}
+	return result{success: true, message: fmt.Sprintf("Order %d cancelled", orderId), order: cancelled}
+}
+
+// cancelResting takes an order out of book and reports why.
+func cancelResting(m *market, book *orderbook.Book, orderId uint64, reason string) orderbook.Order {
+	cancelled, _ := book.Remove(orderId)
+	m.report(cancelled, pb.ExecType_EXEC_TYPE_CANCELLED, reason)
+	m.writeCancel(cancelRecord(m.symbol, cancelled))
+	return cancelled
+}
+
+// processAmend changes price and/or amount of a resting order. Reducing the amount keeps the order's time priority,
+// any other change re-enters the order as if it just arrived, so it may trade immediately.
+func processAmend(m *market, orderId uint64, userId int32, price int64, amount int32, timestamp int64) result {
+	book, order, err := findOwnOrder(m, orderId, userId)
+	if err != nil {
+		return result{message: err.Error()}
+	}
+	if price < 0 || amount < 0 {
+		return result{message: fmt.Sprintf("Invalid amendment of order %d: price %d, amount %d", orderId, price, amount)}
+	}
+	if price == 0 {
+		price = order.Price
+	}
+	if amount == 0 {
+		amount = order.Amount + order.Hidden
+	}
+
+	if order.PostOnly != "" {
+		amended := *order
+		amended.Price = price
+		if repriced, crosses := postOnlyPrice(m, amended); crosses {
+			if order.PostOnly == orderbook.PostOnlyReject || repriced <= 0 {
+				return result{message: fmt.Sprintf("Order %d is post-only and would take liquidity at %d", orderId, price)}
 			}
+			price = repriced
 		}
-	} else if order.Type == "SELL" {
-		for e.buyBook.Len() > 0 && remainingAmount > 0 {
-			if top, ok := e.buyBook.Peek(); ok {
-				if top.Price < order.Price {
-					return remainingAmount, matches
-				}
-				if top.Amount > remainingAmount {
-					matches = append(matches, Match{top.Id, order.Id, remainingAmount, top.Price})
-					top.Amount -= remainingAmount
-					return 0, matches
-				} else {
-					matches = append(matches, Match{top.Id, order.Id, top.Amount, top.Price})
-					remainingAmount -= top.Amount
-					heap.Pop(e.buyBook)
-				}
+	}
+
+	// Icebergs are sliced again, which puts the new slice behind the others at its price anyway.
+	if price == order.Price && amount <= order.Amount && order.DisplayAmount == 0 {
+		// Reducing size in place keeps the order's place in the queue of its price.
+		book.Reduce(order, order.Amount-amount)
+		m.report(*order, pb.ExecType_EXEC_TYPE_REPLACED, "")
+		return result{success: true, message: fmt.Sprintf("Order %d amended", orderId), order: *order, keptPriority: true}
+	}
+
+	amended, _ := book.Remove(orderId)
+	amended.Price = price
+	amended.Amount = amount
+	amended.Hidden = 0
+	amended.Time = timestamp
+	m.report(amended, pb.ExecType_EXEC_TYPE_REPLACED, "")
+	result := executeOrder(m, amended)
+	result.message = fmt.Sprintf("Order %d amended", orderId)
+	return result
+}
+
+// match trades order against the opposite book, best price first, until it's filled or the best price is past its
+// limit. Both sides of every trade are reported and settled. If the order meets an order of its own trader, its
+// self-trade prevention mode decides which of them is cancelled instead. prevented tells why the remainder was cancelled
+// if that happened, or if the order ran out of budget.
+func match(m *market, order orderbook.Order) (remaining int32, matches []Match, prevented string) {
+	book := oppositeBook(m, order.Type)
+	limit := priceLimit(m, order)
+	taker := order
+	matches = make([]Match, 0)
+	for book.Len() > 0 && taker.Amount > 0 {
+		top, _ := book.Peek()
+		if (order.Type == orderbook.Buy && top.Price > limit) || (order.Type == orderbook.Sell && top.Price < limit) {
+			break
+		}
+		if order.SelfTradePrevention != "" && selfTrade(order, *top) {
+			if preventSelfTrade(m, book, &taker, top) {
+				return taker.Amount, matches, stpReason(order.SelfTradePrevention)
+			}
+			continue
+		}
+
+		amount, price := min(taker.Amount, top.Amount), top.Price
+		if m.accounts != nil && budgeted(order) {
+			if amount = int32(min(int64(amount), m.fees.affordable(taker.Budget, price))); amount == 0 {
+				return taker.Amount, matches, "insufficient funds"
 			}
 		}
+		m.lastTradeId++
+		trade := Match{tradeId: m.lastTradeId, time: m.commandTime, symbol: m.symbol, aggressor: order.Type, amount: amount, price: price}
+		if order.Type == orderbook.Buy {
+			trade.buyId, trade.buyUserId, trade.sellId, trade.sellUserId = order.Id, order.UserID, top.Id, top.UserID
+		} else {
+			trade.buyId, trade.buyUserId, trade.sellId, trade.sellUserId = top.Id, top.UserID, order.Id, order.UserID
+		}
+		m.fees.charge(&trade)
+		matches = append(matches, trade)
+		if m.accounts != nil {
+			buyerFee, sellerFee := trade.fees()
+			if err := m.accounts.Settle(trade.tradeId, trade.time, trade.buyUserId, trade.sellUserId, amount, price, buyerFee, sellerFee); err != nil {
+				log.Fatalf("Failed to settle %s trade %d: %v", m.symbol, trade.tradeId, err)
+			}
+		}
+		if top.Amount > amount {
+			book.Fill(top, amount)
+			m.reportFill(*top, amount, price, trade.makerFee, true)
+		} else {
+			m.reportFill(filledOrder(*top), amount, price, trade.makerFee, true)
+			filled, _ := book.Pop()
+			replenish(m, filled)
+		}
+		taker.Amount -= amount
+		taker.Filled += amount
+		if budgeted(order) {
+			taker.Budget -= int64(amount)*price + trade.takerFee
+		}
+		m.reportFill(taker, amount, price, trade.takerFee, false)
+	}
+	return taker.Amount, matches, ""
+}
+
+// priceLimit returns the worst price order may trade at. That's the limit price of LIMIT orders, MARKET orders sweep the
+// book up to their slippage protection and the collar around the last trade.
+func priceLimit(m *market, order orderbook.Order) int64 {
+	if order.OrderType != orderbook.Market {
+		return order.Price
 	}
-	return remainingAmount, matches
+
+	// Prices further away than any protection are expressed as the extremes of the opposite side.
+	buy := order.Type == orderbook.Buy
+	limit := int64(math.MaxInt64)
+	if !buy {
+		limit = math.MinInt64
+	}
+	tighten := func(price int64) {
+		if buy {
+			limit = min(limit, price)
+		} else {
+			limit = max(limit, price)
+		}
+	}
+	ticks := func(n int64) int64 {
+		if buy {
+			return n * m.tickSize
+		}
+		return -n * m.tickSize
+	}
+
+	if best, ok := oppositeBook(m, order.Type).Peek(); ok && order.MaxSlippageTicks > 0 {
+		tighten(best.Price + ticks(int64(order.MaxSlippageTicks)))
+	}
+	if m.collarTicks > 0 && m.lastTrade > 0 {
+		tighten(m.lastTrade + ticks(m.collarTicks))
+	}
+	return limit
+}
+
+// fillable tells whether order can be executed completely right away, without changing the books. Iceberg orders count
+// with their hidden amount, as their slices refill behind the orders at their price. Orders of its own trader don't
+// count when self-trade prevention would remove them, and nothing past them counts when it would cancel order. Budgeted orders only count what their budget buys, as if every trade paid the highest fees.
+func fillable(m *market, order orderbook.Order) bool {
+	limit := priceLimit(m, order)
+	left, budget := int64(order.Amount), order.Budget
+	oppositeBook(m, order.Type).Each(func(level orderbook.Level, orders []orderbook.Order) bool {
+		if (order.Type == orderbook.Buy && level.Price > limit) || (order.Type == orderbook.Sell && level.Price < limit) {
+			return false
+		}
+		var available, hidden int64
+		done := false
+		for _, maker := range orders {
+			if order.SelfTradePrevention == "" || !selfTrade(order, maker) {
+				available += int64(maker.Amount)
+				hidden += int64(maker.Hidden)
+			} else if order.SelfTradePrevention == orderbook.CancelNewest || order.SelfTradePrevention == orderbook.CancelBoth {
+				done = true
+				break
+			}
+		}
+		if !done {
+			available += hidden
+		}
+
+		amount := min(left, available)
+		if m.accounts != nil && budgeted(order) {
+			amount = min(amount, m.fees.affordable(budget, level.Price))
+			cost := amount * level.Price
+			budget -= cost + m.fees.reserve(cost)
+		}
+		left -= amount
+		return left > 0 && !done
+	})
+	return left == 0
+}
+
+// oppositeBook returns the book that orders of the given side trade against.
+func oppositeBook(m *market, side orderbook.Side) *orderbook.Book {
+	if side == orderbook.Buy {
+		return m.sellBook
+	}
+	return m.buyBook
+}
+
+// postOnlyPrice returns the price a post-only order can rest at without taking liquidity, which is one tick behind the
+// best opposite price if the order's own price crosses it.
+func postOnlyPrice(m *market, order orderbook.Order) (price int64, crosses bool) {
+	opposite := oppositeBook(m, order.Type)
+	if opposite.Len() == 0 {
+		return order.Price, false
+	}
+	best, _ := opposite.Peek()
+	if order.Type == orderbook.Buy && order.Price >= best.Price {
+		return best.Price - m.tickSize, true
+	}
+	if order.Type == orderbook.Sell && order.Price <= best.Price {
+		return best.Price + m.tickSize, true
+	}
+	return order.Price, false
+}
+
+// replenish rests the next slice of an iceberg order whose resting slice was executed. The slice gets a new timestamp,
+// so it queues behind the orders already resting at its price. Its expiry is still tracked from the first slice.
+func replenish(m *market, order orderbook.Order) {
+	if order.Hidden == 0 {
+		return
+	}
+	order.Filled += order.Amount
+	order.Amount = min(order.DisplayAmount, order.Hidden)
+	order.Hidden -= order.Amount
+	order.Time = m.commandTime
+	if order.Type == orderbook.Buy {
+		m.buyBook.Add(order)
+	} else {
+		m.sellBook.Add(order)
+	}
+}
+
+// filledOrder returns the state of a resting order once the rest of it is filled.
+func filledOrder(order orderbook.Order) orderbook.Order {
+	order.Filled += order.Amount
+	order.Amount = 0
+	return order
 }
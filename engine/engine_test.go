@@ -1,61 +1,93 @@
 package engine
 
 import (
-	"container/heap"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MichalPitr/exchange/accounts"
+	"github.com/MichalPitr/exchange/instrument"
+	"github.com/MichalPitr/exchange/ledger"
 	"github.com/MichalPitr/exchange/orderbook"
+	pb "github.com/MichalPitr/exchange/protos"
+	"github.com/MichalPitr/exchange/reporter"
 )
 
-func TestMatchOneBuyTwoSells(t *testing.T) {
-	engine, err := New(32)
+const testSymbol = "TEST"
+
+func newTestEngine(t *testing.T) *Engine {
+	instruments, err := instrument.NewRegistry(instrument.Instrument{Symbol: testSymbol})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := New(Config{QueueSize: 32, Instruments: instruments, DataDir: t.TempDir()})
 	if err != nil {
 		t.Fatal(err)
 	}
+	return engine
+}
+
+func newTestMarket(t *testing.T) *market {
+	return newTestEngine(t).markets[testSymbol]
+}
+
+// sequence runs cmd through the market like processCommands does and returns its result.
+func sequence(m *market, cmd command) result {
+	cmd.symbol = m.symbol
+	cmd.resultChan = make(chan result, 1)
+	processBatch(m, []command{cmd})
+	return <-cmd.resultChan
+}
+
+func TestMatchOneBuyTwoSells(t *testing.T) {
+	m := newTestMarket(t)
 	orders := []orderbook.Order{
 		{
-			UserID:     1,
-			Type:       "SELL",
-			OrderType:  "LIMIT",
-			Amount:     10,
-			Price:      100,
-			Time:       1641016800, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    1,
+			Type:      "SELL",
+			OrderType: "LIMIT",
+			Amount:    10,
+			Price:     100,
+			Time:      1641016800, // Example Unix timestamp
 		},
 		{
-			UserID:     2,
-			Type:       "SELL",
-			OrderType:  "LIMIT",
-			Amount:     10,
-			Price:      200,
-			Time:       1641103200, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    2,
+			Type:      "SELL",
+			OrderType: "LIMIT",
+			Amount:    10,
+			Price:     200,
+			Time:      1641103200, // Example Unix timestamp
 		},
 		{
-			UserID:     3,
-			Type:       "SELL",
-			OrderType:  "LIMIT",
-			Amount:     10,
-			Price:      150,
-			Time:       1641189600, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    3,
+			Type:      "SELL",
+			OrderType: "LIMIT",
+			Amount:    10,
+			Price:     150,
+			Time:      1641189600, // Example Unix timestamp
 		},
 	}
 
 	for _, order := range orders {
-		heap.Push(engine.sellBook, orderbook.Item{Order: order})
+		m.sellBook.Add(order)
 	}
 
 	buy := orderbook.Order{
-		UserID:     1,
-		Type:       "BUY",
-		OrderType:  "LIMIT",
-		Amount:     20,
-		Price:      200,
-		Time:       1641016800, // Example Unix timestamp
-		ResultChan: nil,        // Or initialize as appropriate
-	}
-	remainder, matches := match(engine, buy)
+		UserID:    1,
+		Type:      "BUY",
+		OrderType: "LIMIT",
+		Amount:    20,
+		Price:     200,
+		Time:      1641016800, // Example Unix timestamp
+	}
+	remainder, matches, _ := match(m, buy)
 	if remainder != 0 {
 		t.Errorf("Expected 0 remainder, but got %d", remainder)
 	}
@@ -65,55 +97,48 @@ func TestMatchOneBuyTwoSells(t *testing.T) {
 }
 
 func TestMatchOneBuyPartialSells(t *testing.T) {
-	engine, err := New(32)
-	if err != nil {
-		t.Fatal(err)
-	}
+	m := newTestMarket(t)
 	orders := []orderbook.Order{
 		{
-			UserID:     1,
-			Type:       "SELL",
-			OrderType:  "LIMIT",
-			Amount:     10,
-			Price:      100,
-			Time:       1641016800, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    1,
+			Type:      "SELL",
+			OrderType: "LIMIT",
+			Amount:    10,
+			Price:     100,
+			Time:      1641016800, // Example Unix timestamp
 		},
 		{
-			UserID:     2,
-			Type:       "SELL",
-			OrderType:  "LIMIT",
-			Amount:     10,
-			Price:      200,
-			Time:       1641103200, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    2,
+			Type:      "SELL",
+			OrderType: "LIMIT",
+			Amount:    10,
+			Price:     200,
+			Time:      1641103200, // Example Unix timestamp
 		},
 		{
-			UserID:     3,
-			Type:       "SELL",
-			OrderType:  "LIMIT",
-			Amount:     10,
-			Price:      150,
-			Time:       1641189600, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    3,
+			Type:      "SELL",
+			OrderType: "LIMIT",
+			Amount:    10,
+			Price:     150,
+			Time:      1641189600, // Example Unix timestamp
 		},
 	}
 
 	for _, order := range orders {
-		heap.Push(engine.sellBook, orderbook.Item{Order: order})
+		m.sellBook.Add(order)
 	}
 
 	buy := orderbook.Order{
-		UserID:     1,
-		Type:       "BUY",
-		OrderType:  "LIMIT",
-		Amount:     15,
-		Price:      200,
-		Time:       1641016800, // Example Unix timestamp
-		ResultChan: nil,        // Or initialize as appropriate
+		UserID:    1,
+		Type:      "BUY",
+		OrderType: "LIMIT",
+		Amount:    15,
+		Price:     200,
+		Time:      1641016800, // Example Unix timestamp
 	}
 
-	remainder, matches := match(engine, buy)
+	remainder, matches, _ := match(m, buy)
 	if remainder != 0 {
 		t.Errorf("Expected 0 remainder, but got %d", remainder)
 	}
@@ -121,7 +146,7 @@ func TestMatchOneBuyPartialSells(t *testing.T) {
 		t.Errorf("Expected 2 matches, but got %d", len(matches))
 	}
 
-	if order, ok := engine.sellBook.Peek(); ok {
+	if order, ok := m.sellBook.Peek(); ok {
 		if order.Amount != 5 {
 			t.Errorf("Expected top sell order to have quantity 5, but has %d", order.Amount)
 		}
@@ -129,36 +154,31 @@ func TestMatchOneBuyPartialSells(t *testing.T) {
 }
 
 func TestMatchBuyNoMatch(t *testing.T) {
-	engine, err := New(32)
-	if err != nil {
-		t.Fatal(err)
-	}
+	m := newTestMarket(t)
 	orders := []orderbook.Order{
 		{
-			UserID:     1,
-			Type:       "SELL",
-			OrderType:  "LIMIT",
-			Amount:     10,
-			Price:      100,
-			Time:       1641016800, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    1,
+			Type:      "SELL",
+			OrderType: "LIMIT",
+			Amount:    10,
+			Price:     100,
+			Time:      1641016800, // Example Unix timestamp
 		},
 	}
 
 	for _, order := range orders {
-		heap.Push(engine.sellBook, orderbook.Item{Order: order})
+		m.sellBook.Add(order)
 	}
 
 	buy := orderbook.Order{
-		UserID:     1,
-		Type:       "BUY",
-		OrderType:  "LIMIT",
-		Amount:     10,
-		Price:      50,         // Price set below sell price.
-		Time:       1641016800, // Example Unix timestamp
-		ResultChan: nil,        // Or initialize as appropriate
-	}
-	remainder, matches := match(engine, buy)
+		UserID:    1,
+		Type:      "BUY",
+		OrderType: "LIMIT",
+		Amount:    10,
+		Price:     50,         // Price set below sell price.
+		Time:      1641016800, // Example Unix timestamp
+	}
+	remainder, matches, _ := match(m, buy)
 	if remainder != 10 {
 		t.Errorf("Expected 10 remainder, but got %d", remainder)
 	}
@@ -166,7 +186,7 @@ func TestMatchBuyNoMatch(t *testing.T) {
 		t.Errorf("Expected 0 matches, but got %d", len(matches))
 	}
 
-	if order, ok := engine.sellBook.Peek(); ok {
+	if order, ok := m.sellBook.Peek(); ok {
 		if order.Amount != 10 {
 			t.Errorf("Sellbook shouldn't change when match fails.")
 		}
@@ -174,97 +194,85 @@ func TestMatchBuyNoMatch(t *testing.T) {
 }
 
 func TestMatchOneSellTwoBuys(t *testing.T) {
-	engine, err := New(32)
-	if err != nil {
-		t.Fatal(err)
-	}
+	m := newTestMarket(t)
 	orders := []orderbook.Order{
 		{
-			UserID:     1,
-			Type:       "BUY",
-			OrderType:  "LIMIT",
-			Amount:     10,
-			Price:      100,
-			Time:       1641016800, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    1,
+			Type:      "BUY",
+			OrderType: "LIMIT",
+			Amount:    10,
+			Price:     100,
+			Time:      1641016800, // Example Unix timestamp
 		},
 		{
-			UserID:     2,
-			Type:       "BUY",
-			OrderType:  "LIMIT",
-			Amount:     10,
-			Price:      200,
-			Time:       1641103200, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    2,
+			Type:      "BUY",
+			OrderType: "LIMIT",
+			Amount:    10,
+			Price:     200,
+			Time:      1641103200, // Example Unix timestamp
 		},
 		{
-			UserID:     3,
-			Type:       "BUY",
-			OrderType:  "LIMIT",
-			Amount:     10,
-			Price:      150,
-			Time:       1641189600, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    3,
+			Type:      "BUY",
+			OrderType: "LIMIT",
+			Amount:    10,
+			Price:     150,
+			Time:      1641189600, // Example Unix timestamp
 		},
 	}
 
 	for _, order := range orders {
-		heap.Push(engine.buyBook, orderbook.Item{Order: order})
+		m.buyBook.Add(order)
 	}
 
 	sell := orderbook.Order{
-		UserID:     1,
-		Type:       "SELL",
-		OrderType:  "LIMIT",
-		Amount:     20,
-		Price:      150,
-		Time:       1641016800, // Example Unix timestamp
-		ResultChan: nil,        // Or initialize as appropriate
+		UserID:    1,
+		Type:      "SELL",
+		OrderType: "LIMIT",
+		Amount:    20,
+		Price:     150,
+		Time:      1641016800, // Example Unix timestamp
 	}
 
-	if remainder, _ := match(engine, sell); remainder != 0 {
+	if remainder, _, _ := match(m, sell); remainder != 0 {
 		t.Error("Expected a match")
 	}
 }
 
 func TestProcessLimitOrder(t *testing.T) {
-	engine, err := New(32)
-	if err != nil {
-		t.Fatal(err)
-	}
+	m := newTestMarket(t)
 	orders := []orderbook.Order{
 		{
-			UserID:     1,
-			Type:       "BUY",
-			OrderType:  "LIMIT",
-			Amount:     10,
-			Price:      100,
-			Time:       1641016800, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    1,
+			Type:      "BUY",
+			OrderType: "LIMIT",
+			Amount:    10,
+			Price:     100,
+			Time:      1641016800, // Example Unix timestamp
 		},
 	}
 
 	for _, order := range orders {
-		heap.Push(engine.buyBook, orderbook.Item{Order: order})
+		m.buyBook.Add(order)
 	}
 
-	if engine.sellBook.Len() != 0 {
+	if m.sellBook.Len() != 0 {
 		t.Error("Expected empty sellbook.")
 	}
 
 	sell := orderbook.Order{
-		UserID:     1,
-		Type:       "SELL",
-		OrderType:  "LIMIT",
-		Amount:     20, // Amount higher than demand in orderbook
-		Price:      100,
-		Time:       1641016800, // Example Unix timestamp
-		ResultChan: nil,        // Or initialize as appropriate
+		UserID:    1,
+		Type:      "SELL",
+		OrderType: "LIMIT",
+		Amount:    20, // Amount higher than demand in orderbook
+		Price:     100,
+		Time:      1641016800, // Example Unix timestamp
 	}
 
-	processOrder(engine, sell)
+	processOrder(m, sell)
 
-	if order, ok := engine.sellBook.Peek(); ok {
+	if order, ok := m.sellBook.Peek(); ok {
 		if order.Amount != 10 {
 			t.Errorf("Expected 10 units of order to be added to orderbook, instead got %d", order.Amount)
 		}
@@ -274,43 +282,1313 @@ func TestProcessLimitOrder(t *testing.T) {
 }
 
 func TestProcessMarketOrder(t *testing.T) {
-	engine, err := New(32)
-	if err != nil {
-		t.Fatal(err)
-	}
+	m := newTestMarket(t)
 	orders := []orderbook.Order{
 		{
-			UserID:     1,
-			Type:       "BUY",
-			OrderType:  "LIMIT", // Buy is limit so that it is added to orderbook.
-			Amount:     10,
-			Price:      100,
-			Time:       1641016800, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    1,
+			Type:      "BUY",
+			OrderType: "LIMIT", // Buy is limit so that it is added to orderbook.
+			Amount:    10,
+			Price:     100,
+			Time:      1641016800, // Example Unix timestamp
 		},
 	}
 
 	for _, order := range orders {
-		heap.Push(engine.buyBook, orderbook.Item{Order: order})
+		m.buyBook.Add(order)
 	}
 
-	if engine.sellBook.Len() != 0 {
+	if m.sellBook.Len() != 0 {
 		t.Error("Expected empty sellbook.")
 	}
 
 	sell := orderbook.Order{
-		UserID:     1,
-		Type:       "SELL",
-		OrderType:  "MARKET",
-		Amount:     20, // Amount higher than demand in orderbook
-		Price:      100,
-		Time:       1641016800, // Example Unix timestamp
-		ResultChan: nil,        // Or initialize as appropriate
+		UserID:    1,
+		Type:      "SELL",
+		OrderType: "MARKET",
+		Amount:    20, // Amount higher than demand in orderbook
+		Price:     100,
+		Time:      1641016800, // Example Unix timestamp
 	}
 
-	processOrder(engine, sell)
+	processOrder(m, sell)
 
-	if order, ok := engine.sellBook.Peek(); ok {
+	if order, ok := m.sellBook.Peek(); ok {
 		t.Errorf("Expected market order to not be added to sell orderbook. %v", order)
 	}
 }
+
+func TestCancelOrder(t *testing.T) {
+	m := newTestMarket(t)
+	orders := []orderbook.Order{
+		{Id: 1, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 100, Time: 1641016800},
+		{Id: 2, UserID: 2, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 200, Time: 1641103200},
+	}
+	for _, order := range orders {
+		m.buyBook.Add(order)
+	}
+
+	if result := processCancel(m, 2, 1); result.success {
+		t.Error("Expected cancel from non-owner to be rejected.")
+	}
+	if result := processCancel(m, 3, 1); result.success {
+		t.Error("Expected cancel of unknown order to be rejected.")
+	}
+	if result := processCancel(m, 2, 2); !result.success {
+		t.Errorf("Expected cancel to succeed, got: %s", result.message)
+	}
+
+	if m.buyBook.Len() != 1 {
+		t.Errorf("Expected 1 order left in buy book, but got %d", m.buyBook.Len())
+	}
+	if order, ok := m.buyBook.Peek(); ok && order.Id != 1 {
+		t.Errorf("Expected order 1 at the top of the buy book, but got %d", order.Id)
+	}
+}
+
+func TestAmendReduceKeepsPriority(t *testing.T) {
+	m := newTestMarket(t)
+	orders := []orderbook.Order{
+		{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 100, Time: 1641016800},
+		{Id: 2, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 100, Time: 1641103200},
+	}
+	for _, order := range orders {
+		m.sellBook.Add(order)
+	}
+
+	result := processAmend(m, 1, 1, 0, 4, 1641189600)
+	if !result.success || !result.keptPriority {
+		t.Fatalf("Expected size reduction to keep priority, got: %+v", result)
+	}
+	if order, ok := m.sellBook.Peek(); !ok || order.Id != 1 || order.Amount != 4 {
+		t.Errorf("Expected order 1 with amount 4 at the top of the sell book, but got %v", order)
+	}
+
+	result = processAmend(m, 1, 1, 0, 6, 1641276000)
+	if !result.success || result.keptPriority {
+		t.Fatalf("Expected size increase to lose priority, got: %+v", result)
+	}
+	if order, ok := m.sellBook.Peek(); !ok || order.Id != 2 {
+		t.Errorf("Expected order 2 at the top of the sell book, but got %v", order)
+	}
+}
+
+func TestAmendPriceCrosses(t *testing.T) {
+	m := newTestMarket(t)
+	m.sellBook.Add(orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: 150, Time: 1641016800})
+	m.buyBook.Add(orderbook.Order{Id: 2, UserID: 2, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 100, Time: 1641103200})
+
+	if result := processAmend(m, 2, 1, 150, 0, 1641189600); result.success {
+		t.Error("Expected amend from non-owner to be rejected.")
+	}
+
+	result := processAmend(m, 2, 2, 150, 0, 1641189600)
+	if !result.success {
+		t.Fatalf("Expected amend to succeed, got: %s", result.message)
+	}
+	if len(result.matches) != 1 || result.matches[0].amount != 5 || result.matches[0].price != 150 {
+		t.Errorf("Expected a single fill of 5 at 150, but got %v", result.matches)
+	}
+	if result.order.Amount != 5 {
+		t.Errorf("Expected 5 left resting, but got %d", result.order.Amount)
+	}
+	if order, ok := m.buyBook.Peek(); !ok || order.Price != 150 || order.Amount != 5 {
+		t.Errorf("Expected amended order to rest at 150 with amount 5, but got %v", order)
+	}
+	if m.sellBook.Len() != 0 {
+		t.Errorf("Expected empty sell book, but has %d orders", m.sellBook.Len())
+	}
+}
+
+func TestSendOrderReturnsFills(t *testing.T) {
+	engine := newTestEngine(t)
+	go ProcessOrders(engine)
+
+	ctx := context.Background()
+	sell, err := engine.SendOrder(ctx, &pb.OrderRequest{UserId: 1, Symbol: testSymbol, Side: pb.Side_SIDE_SELL, OrderType: pb.OrderType_ORDER_TYPE_LIMIT, Amount: 10, Price: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sell.OrderStatus != pb.OrderStatus_ORDER_STATUS_RESTING {
+		t.Errorf("Expected sell to rest, but got %v", sell.OrderStatus)
+	}
+
+	buy, err := engine.SendOrder(ctx, &pb.OrderRequest{UserId: 2, Symbol: testSymbol, Side: pb.Side_SIDE_BUY, OrderType: pb.OrderType_ORDER_TYPE_MARKET, Amount: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buy.OrderStatus != pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED {
+		t.Errorf("Expected market buy remainder to be cancelled, but got %v", buy.OrderStatus)
+	}
+	if buy.ExecutedAmount != 10 || buy.AveragePrice != 100 || buy.UnfilledAmount != 5 {
+		t.Errorf("Expected 10 executed at 100, but got %d at %f", buy.ExecutedAmount, buy.AveragePrice)
+	}
+	if len(buy.Fills) != 1 || buy.Fills[0].SellOrderId != sell.OrderId || buy.Fills[0].BuyOrderId != buy.OrderId {
+		t.Errorf("Expected a single fill against order %d, but got %v", sell.OrderId, buy.Fills)
+	}
+
+	_, err = engine.SendOrder(ctx, &pb.OrderRequest{UserId: 2, Symbol: testSymbol, Side: 7, OrderType: pb.OrderType_ORDER_TYPE_LIMIT, Amount: 15, Price: 100})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected unknown side to be rejected as invalid, but got %v", err)
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	valid := func(modify func(*pb.OrderRequest)) *pb.OrderRequest {
+		request := &pb.OrderRequest{UserId: 1, Symbol: testSymbol, Side: pb.Side_SIDE_BUY, OrderType: pb.OrderType_ORDER_TYPE_LIMIT, Amount: 10, Price: 100}
+		modify(request)
+		return request
+	}
+	tests := []struct {
+		request *pb.OrderRequest
+		reason  pb.RejectReason
+	}{
+		{valid(func(r *pb.OrderRequest) {}), pb.RejectReason_REJECT_REASON_UNSPECIFIED},
+		{valid(func(r *pb.OrderRequest) { r.OrderType, r.Price = pb.OrderType_ORDER_TYPE_MARKET, 0 }), pb.RejectReason_REJECT_REASON_UNSPECIFIED},
+		{valid(func(r *pb.OrderRequest) { r.Symbol = "" }), pb.RejectReason_REJECT_REASON_MISSING_SYMBOL},
+		{valid(func(r *pb.OrderRequest) { r.Side = pb.Side_SIDE_UNSPECIFIED }), pb.RejectReason_REJECT_REASON_INVALID_SIDE},
+		{valid(func(r *pb.OrderRequest) { r.OrderType = 9 }), pb.RejectReason_REJECT_REASON_INVALID_ORDER_TYPE},
+		{valid(func(r *pb.OrderRequest) { r.Amount = 0 }), pb.RejectReason_REJECT_REASON_INVALID_AMOUNT},
+		{valid(func(r *pb.OrderRequest) { r.Price = -5 }), pb.RejectReason_REJECT_REASON_INVALID_PRICE},
+		{valid(func(r *pb.OrderRequest) { r.TimeInForce = pb.TimeInForce_TIME_IN_FORCE_FOK }), pb.RejectReason_REJECT_REASON_UNSPECIFIED},
+		{valid(func(r *pb.OrderRequest) { r.TimeInForce = 42 }), pb.RejectReason_REJECT_REASON_INVALID_TIME_IN_FORCE},
+		{valid(func(r *pb.OrderRequest) {
+			r.OrderType, r.TimeInForce = pb.OrderType_ORDER_TYPE_MARKET, pb.TimeInForce_TIME_IN_FORCE_DAY
+		}), pb.RejectReason_REJECT_REASON_INVALID_TIME_IN_FORCE},
+		{valid(func(r *pb.OrderRequest) { r.TimeInForce = pb.TimeInForce_TIME_IN_FORCE_GTD }), pb.RejectReason_REJECT_REASON_INVALID_EXPIRE_TIME},
+		{valid(func(r *pb.OrderRequest) { r.ExpireTime = time.Now().Add(time.Hour).UnixNano() }), pb.RejectReason_REJECT_REASON_INVALID_EXPIRE_TIME},
+		{valid(func(r *pb.OrderRequest) { r.DisplayAmount = 4 }), pb.RejectReason_REJECT_REASON_UNSPECIFIED},
+		{valid(func(r *pb.OrderRequest) { r.DisplayAmount = 10 }), pb.RejectReason_REJECT_REASON_INVALID_DISPLAY_AMOUNT},
+		{valid(func(r *pb.OrderRequest) {
+			r.DisplayAmount, r.TimeInForce = 4, pb.TimeInForce_TIME_IN_FORCE_IOC
+		}), pb.RejectReason_REJECT_REASON_INVALID_DISPLAY_AMOUNT},
+		{valid(func(r *pb.OrderRequest) { r.PostOnly = pb.PostOnly_POST_ONLY_REPRICE }), pb.RejectReason_REJECT_REASON_UNSPECIFIED},
+		{valid(func(r *pb.OrderRequest) { r.PostOnly = 7 }), pb.RejectReason_REJECT_REASON_INVALID_POST_ONLY},
+		{valid(func(r *pb.OrderRequest) {
+			r.OrderType, r.Price, r.PostOnly = pb.OrderType_ORDER_TYPE_MARKET, 0, pb.PostOnly_POST_ONLY_REJECT
+		}), pb.RejectReason_REJECT_REASON_INVALID_POST_ONLY},
+	}
+	for _, test := range tests {
+		err := validateOrder(test.request, time.Now())
+		if test.reason == pb.RejectReason_REJECT_REASON_UNSPECIFIED {
+			if err != nil {
+				t.Errorf("Expected %v to be valid, but got %v", test.request, err)
+			}
+			continue
+		}
+
+		st := status.Convert(err)
+		if st.Code() != codes.InvalidArgument || len(st.Details()) != 1 {
+			t.Errorf("Expected %v to be an invalid argument with details, but got %v", test.request, err)
+			continue
+		}
+		if info, ok := st.Details()[0].(*errdetails.ErrorInfo); !ok || info.Reason != test.reason.String() {
+			t.Errorf("Expected %v to be rejected with %v, but got %v", test.request, test.reason, st.Details()[0])
+		}
+	}
+}
+
+func TestSendOrderUnknownSymbol(t *testing.T) {
+	engine := newTestEngine(t)
+
+	response, err := engine.SendOrder(context.Background(), &pb.OrderRequest{UserId: 1, Symbol: "NOPE", Side: pb.Side_SIDE_SELL, OrderType: pb.OrderType_ORDER_TYPE_LIMIT, Amount: 10, Price: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.OrderStatus != pb.OrderStatus_ORDER_STATUS_REJECTED {
+		t.Errorf("Expected order for unknown symbol to be rejected, but got %v", response.OrderStatus)
+	}
+	if response.RejectReason != pb.RejectReason_REJECT_REASON_UNKNOWN_SYMBOL {
+		t.Errorf("Expected unknown symbol reject reason, but got %v", response.RejectReason)
+	}
+}
+
+func TestMarketsAreIndependent(t *testing.T) {
+	instruments, err := instrument.NewRegistry(instrument.Instrument{Symbol: "AAA"}, instrument.Instrument{Symbol: "BBB"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := New(Config{QueueSize: 32, Instruments: instruments, DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ProcessOrders(engine)
+	defer engine.Close()
+
+	ctx := context.Background()
+	sell, err := engine.SendOrder(ctx, &pb.OrderRequest{UserId: 1, Symbol: "AAA", Side: pb.Side_SIDE_SELL, OrderType: pb.OrderType_ORDER_TYPE_LIMIT, Amount: 10, Price: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buy, err := engine.SendOrder(ctx, &pb.OrderRequest{UserId: 2, Symbol: "BBB", Side: pb.Side_SIDE_BUY, OrderType: pb.OrderType_ORDER_TYPE_LIMIT, Amount: 10, Price: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buy.OrderStatus != pb.OrderStatus_ORDER_STATUS_RESTING || sell.OrderStatus != pb.OrderStatus_ORDER_STATUS_RESTING {
+		t.Errorf("Expected orders in different markets to not trade, but got %v and %v", sell.OrderStatus, buy.OrderStatus)
+	}
+	if buy.OrderId == sell.OrderId {
+		t.Errorf("Expected unique order ids across markets, but both are %d", buy.OrderId)
+	}
+}
+
+func TestRecoverFromJournal(t *testing.T) {
+	instruments, err := instrument.NewRegistry(instrument.Instrument{Symbol: testSymbol})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{QueueSize: 32, Instruments: instruments, DataDir: t.TempDir()}
+	engine, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ProcessOrders(engine)
+
+	ctx := context.Background()
+	requests := []*pb.OrderRequest{
+		{UserId: 1, Symbol: testSymbol, Side: pb.Side_SIDE_SELL, OrderType: pb.OrderType_ORDER_TYPE_LIMIT, Amount: 10, Price: 100},
+		{UserId: 2, Symbol: testSymbol, Side: pb.Side_SIDE_SELL, OrderType: pb.OrderType_ORDER_TYPE_LIMIT, Amount: 10, Price: 110},
+		{UserId: 3, Symbol: testSymbol, Side: pb.Side_SIDE_BUY, OrderType: pb.OrderType_ORDER_TYPE_LIMIT, Amount: 15, Price: 110},
+		{UserId: 4, Symbol: testSymbol, Side: pb.Side_SIDE_BUY, OrderType: pb.OrderType_ORDER_TYPE_LIMIT, Amount: 5, Price: 90},
+	}
+	for _, request := range requests {
+		if _, err := engine.SendOrder(ctx, request); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := engine.AmendOrder(ctx, &pb.AmendRequest{OrderId: 3, UserId: 4, Symbol: testSymbol, Price: 95}); err != nil {
+		t.Fatal(err)
+	}
+	engine.Close()
+
+	recovered, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := recovered.markets[testSymbol]
+	if top, ok := m.sellBook.Peek(); !ok || top.Id != 1 || top.Amount != 5 {
+		t.Errorf("Expected order 1 with 5 left at the top of the sell book, but got %v", top)
+	}
+	if top, ok := m.buyBook.Peek(); !ok || top.Id != 3 || top.Price != 95 {
+		t.Errorf("Expected amended order 3 at the top of the buy book, but got %v", top)
+	}
+	if m.seq != 5 {
+		t.Errorf("Expected sequence number 5 after recovery, but got %d", m.seq)
+	}
+	if next := recovered.nextOrderId.Load(); next != 4 {
+		t.Errorf("Expected next order id 4, but got %d", next)
+	}
+
+	trades, err := os.ReadFile(filepath.Join(cfg.DataDir, "trades_TEST.00000000000000000001.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(trades), "\nTRADE,"); lines != 2 {
+		t.Errorf("Expected the 2 trades to be reported once, but trade log has %d trades", lines)
+	}
+	recovered.closeFiles()
+
+	// Trades journaled but lost from the trade log are written again when the journal is replayed.
+	tradeLog := filepath.Join(cfg.DataDir, "trades_TEST.00000000000000000001.log")
+	if err := os.WriteFile(tradeLog, trades[:strings.LastIndex(string(trades), "TRADE,")+10], 0666); err != nil {
+		t.Fatal(err)
+	}
+	recovered, err = New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered.closeFiles()
+	if rewritten, _ := os.ReadFile(tradeLog); string(rewritten) != string(trades) {
+		t.Errorf("Expected the lost trade to be written again, but trade log has %q", rewritten)
+	}
+}
+
+func TestRecoverFromSnapshot(t *testing.T) {
+	instruments, err := instrument.NewRegistry(instrument.Instrument{Symbol: testSymbol})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{QueueSize: 32, Instruments: instruments, DataDir: t.TempDir()}
+	engine, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := engine.markets[testSymbol]
+	orders := []orderbook.Order{
+		{Id: 0, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 100},
+		{Id: 1, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 105},
+		{Id: 2, UserID: 3, Type: "BUY", OrderType: "LIMIT", Amount: 5, Price: 100},
+		{Id: 3, UserID: 4, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 90},
+		{Id: 4, UserID: 5, Type: "SELL", OrderType: "LIMIT", Amount: 3, Price: 90},
+	}
+	for i, order := range orders {
+		sequence(m, command{kind: newOrder, order: order})
+		if i == 1 || i == 3 {
+			takeSnapshot(engine, m)
+			waitForSnapshot(m)
+		}
+	}
+	engine.Close()
+
+	snapshots, err := listSequenced(cfg.DataDir, testSymbol, ".snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 || snapshots[0].seq != 2 || snapshots[1].seq != 4 {
+		t.Fatalf("Expected snapshots at 2 and 4, but got %v", snapshots)
+	}
+	segments, err := listSequenced(cfg.DataDir, testSymbol, ".journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 2 || segments[0].seq != 3 || segments[1].seq != 5 {
+		t.Fatalf("Expected journal segments starting at 3 and 5, but got %v", segments)
+	}
+
+	checkRecovered := func() {
+		recovered, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer recovered.closeFiles()
+
+		m := recovered.markets[testSymbol]
+		if m.seq != 5 {
+			t.Errorf("Expected sequence number 5 after recovery, but got %d", m.seq)
+		}
+		if top, ok := m.sellBook.Peek(); !ok || top.Id != 0 || top.Amount != 5 || m.sellBook.Len() != 2 {
+			t.Errorf("Expected order 0 with 5 left at the top of 2 sells, but got %v", top)
+		}
+		if top, ok := m.buyBook.Peek(); !ok || top.Id != 3 || top.Amount != 7 || m.buyBook.Len() != 1 {
+			t.Errorf("Expected only order 3 with 7 left in the buy book, but got %v", top)
+		}
+		if next := recovered.nextOrderId.Load(); next != 5 {
+			t.Errorf("Expected next order id 5, but got %d", next)
+		}
+		if order, err := recovered.GetOrder(context.Background(), &pb.GetOrderRequest{OrderId: 2}); err != nil ||
+			order.State != pb.OrderState_ORDER_STATE_FILLED || order.AveragePrice != 100 {
+			t.Errorf("Expected order 2 to be remembered as filled at 100, but got %v, %v", order, err)
+		}
+		if order, err := recovered.GetOrder(context.Background(), &pb.GetOrderRequest{OrderId: 3}); err != nil ||
+			order.State != pb.OrderState_ORDER_STATE_PARTIALLY_FILLED || order.OriginalAmount != 10 || order.FilledAmount != 3 {
+			t.Errorf("Expected order 3 to be remembered as partially filled, but got %v, %v", order, err)
+		}
+	}
+	checkRecovered()
+
+	// A corrupted snapshot is skipped in favour of the previous one and a longer replay.
+	data, err := os.ReadFile(snapshots[1].filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(snapshots[1].filename, data, 0666); err != nil {
+		t.Fatal(err)
+	}
+	checkRecovered()
+}
+
+func TestMarketDataFeed(t *testing.T) {
+	m := newTestMarket(t)
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 100}})
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: 100}})
+
+	sub := &subscriber{updates: make(chan *pb.MarketDataUpdate, 16)}
+	sequence(m, command{kind: subscribeMarketData, subscriber: sub})
+	snapshot := (<-sub.updates).GetSnapshot()
+	if len(snapshot.Asks) != 1 || snapshot.Asks[0].Amount != 15 || snapshot.Asks[0].OrderCount != 2 || len(snapshot.Bids) != 0 {
+		t.Fatalf("Expected a single ask level of 15 in the snapshot, but got %v", snapshot)
+	}
+
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 3, UserID: 3, Type: "BUY", OrderType: "LIMIT", Amount: 12, Price: 100}})
+	trade := <-sub.updates
+	if trade.Seq != 1 || trade.GetTrade().Amount != 10 || trade.GetTrade().AggressorSide != pb.Side_SIDE_BUY {
+		t.Errorf("Expected first update to be a trade of 10, but got %v", trade)
+	}
+	trade = <-sub.updates
+	if trade.Seq != 2 || trade.GetTrade().Amount != 2 {
+		t.Errorf("Expected second update to be a trade of 2, but got %v", trade)
+	}
+	level := <-sub.updates
+	if level.Seq != 3 || level.GetLevel().Side != pb.Side_SIDE_SELL || level.GetLevel().Level.Amount != 3 || level.GetLevel().Level.OrderCount != 1 {
+		t.Errorf("Expected ask level to drop to 3, but got %v", level)
+	}
+	if len(sub.updates) != 0 {
+		t.Errorf("Expected no more updates, but got %d", len(sub.updates))
+	}
+}
+
+func TestMarketDataFeedKeepsDepthFilled(t *testing.T) {
+	m := newTestMarket(t)
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 100}})
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: 101}})
+
+	top := &subscriber{depth: 1, updates: make(chan *pb.MarketDataUpdate, 16)}
+	all := &subscriber{updates: make(chan *pb.MarketDataUpdate, 16)}
+	sequence(m, command{kind: subscribeMarketData, subscriber: top})
+	sequence(m, command{kind: subscribeMarketData, subscriber: all})
+	if snapshot := (<-top.updates).GetSnapshot(); len(snapshot.Asks) != 1 || snapshot.Asks[0].Price != 100 {
+		t.Fatalf("Expected only the best ask in the snapshot, but got %v", snapshot)
+	}
+	<-all.updates
+
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 3, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 7, Price: 102}})
+	if len(top.updates) != 0 {
+		t.Errorf("Expected no update for a level past the depth, but got %v", <-top.updates)
+	}
+	if update := <-all.updates; update.Seq != 1 || update.GetLevel().Level.Price != 102 {
+		t.Errorf("Expected the new level at 102, but got %v", update)
+	}
+
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 4, UserID: 2, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 100}})
+	if trade := <-top.updates; trade.Seq != 1 || trade.GetTrade().Amount != 10 {
+		t.Errorf("Expected a trade of 10, but got %v", trade)
+	}
+	if level := <-top.updates; level.Seq != 2 || level.GetLevel().Level.Price != 100 || level.GetLevel().Level.Amount != 0 {
+		t.Errorf("Expected the level at 100 to be gone, but got %v", level)
+	}
+	if level := <-top.updates; level.Seq != 3 || level.GetLevel().Level.Price != 101 || level.GetLevel().Level.Amount != 5 {
+		t.Errorf("Expected the level at 101 to move into the depth, but got %v", level)
+	}
+	if len(top.updates) != 0 {
+		t.Errorf("Expected no more updates, but got %d", len(top.updates))
+	}
+	if len(all.updates) != 2 {
+		t.Errorf("Expected the trade and the emptied level for the full depth, but got %d updates", len(all.updates))
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	m := newTestMarket(t)
+	sub := &subscriber{updates: make(chan *pb.MarketDataUpdate, 2)}
+	sequence(m, command{kind: subscribeMarketData, subscriber: sub})
+
+	for i := 0; i < 3; i++ {
+		order := orderbook.Order{Id: uint64(i), UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: int64(100 + i)}
+		sequence(m, command{kind: newOrder, order: order})
+	}
+
+	received := 0
+	for range sub.updates {
+		received++
+	}
+	if received != 2 {
+		t.Errorf("Expected the snapshot and one update before being dropped, but got %d messages", received)
+	}
+	if len(m.feeds) != 0 {
+		t.Errorf("Expected subscriber to be removed from the feed.")
+	}
+}
+
+func TestExecutionReports(t *testing.T) {
+	m := newTestMarket(t)
+	sub := &executionSubscriber{reports: make(chan *pb.ExecutionReport, 16)}
+	if _, err := m.executions.subscribe(1, 0, sub); err != nil {
+		t.Fatal(err)
+	}
+
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 100}})
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 2, Type: "BUY", OrderType: "LIMIT", Amount: 4, Price: 100}})
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 3, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 3, Price: 90}})
+	sequence(m, command{kind: cancelOrder, orderId: 1, userId: 1})
+
+	expected := []struct {
+		orderId    uint64
+		execType   pb.ExecType
+		remaining  int32
+		cumulative int32
+		maker      bool
+	}{
+		{1, pb.ExecType_EXEC_TYPE_NEW, 10, 0, false},
+		{1, pb.ExecType_EXEC_TYPE_PARTIAL_FILL, 6, 4, true},
+		{3, pb.ExecType_EXEC_TYPE_NEW, 3, 0, false},
+		{1, pb.ExecType_EXEC_TYPE_CANCELLED, 6, 4, false},
+	}
+	for i, want := range expected {
+		report := <-sub.reports
+		if report.Seq != uint64(i+1) || report.OrderId != want.orderId || report.ExecType != want.execType ||
+			report.RemainingAmount != want.remaining || report.CumulativeAmount != want.cumulative || report.Maker != want.maker {
+			t.Errorf("Expected report %d to be %+v, but got %v", i+1, want, report)
+		}
+	}
+
+	// The taker gets its side of the fill too.
+	taker := &executionSubscriber{reports: make(chan *pb.ExecutionReport, 16)}
+	missed, err := m.executions.subscribe(2, 1, taker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missed) != 2 || missed[1].ExecType != pb.ExecType_EXEC_TYPE_FILL || missed[1].LastAmount != 4 || missed[1].Maker {
+		t.Errorf("Expected the taker's ack and fill, but got %v", missed)
+	}
+}
+
+func TestResumeExecutions(t *testing.T) {
+	hub := newExecutionHub()
+	for i := 0; i < executionHistorySize+10; i++ {
+		hub.publish([]*pb.ExecutionReport{{UserId: 1, OrderId: uint64(i)}})
+	}
+
+	missed, err := hub.subscribe(1, executionHistorySize+5, &executionSubscriber{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missed) != 6 || missed[0].Seq != executionHistorySize+5 {
+		t.Errorf("Expected to resume from report %d, but got %v", executionHistorySize+5, missed)
+	}
+	if _, err := hub.subscribe(1, 1, &executionSubscriber{}); err == nil {
+		t.Errorf("Expected resuming from a report that is no longer retained to fail.")
+	}
+	if _, err := hub.subscribe(1, executionHistorySize+12, &executionSubscriber{}); err == nil {
+		t.Errorf("Expected resuming from a report that wasn't sent yet to fail.")
+	}
+}
+
+func TestExecutionSequenceSurvivesRestart(t *testing.T) {
+	instruments, err := instrument.NewRegistry(instrument.Instrument{Symbol: testSymbol})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{QueueSize: 32, Instruments: instruments, DataDir: t.TempDir()}
+	engine, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := engine.markets[testSymbol]
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 100}})
+	takeSnapshot(engine, m)
+	waitForSnapshot(m)
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 100}})
+	engine.closeFiles()
+
+	// The reports before the restart, one from the snapshot and one from the journal, can't be resumed from anymore.
+	recovered, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer recovered.closeFiles()
+	if _, err := recovered.executions.subscribe(1, 2, &executionSubscriber{}); err == nil {
+		t.Errorf("Expected resuming from a report sent before the restart to fail.")
+	}
+	sub := &executionSubscriber{reports: make(chan *pb.ExecutionReport, 4)}
+	if _, err := recovered.executions.subscribe(1, 3, sub); err != nil {
+		t.Fatal(err)
+	}
+	m = recovered.markets[testSymbol]
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 3, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 100}})
+	if report := <-sub.reports; report.Seq != 3 || report.OrderId != 3 {
+		t.Errorf("Expected the sequence to continue with report 3, but got %v", report)
+	}
+}
+
+func TestOrderQueries(t *testing.T) {
+	engine := newTestEngine(t)
+	go ProcessOrders(engine)
+
+	ctx := context.Background()
+	var ids []uint64
+	for _, price := range []int64{100, 101, 102} {
+		sell, err := engine.SendOrder(ctx, &pb.OrderRequest{UserId: 1, Symbol: testSymbol, Side: pb.Side_SIDE_SELL, OrderType: pb.OrderType_ORDER_TYPE_LIMIT, Amount: 10, Price: price})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, sell.OrderId)
+	}
+	buy, err := engine.SendOrder(ctx, &pb.OrderRequest{UserId: 2, Symbol: testSymbol, Side: pb.Side_SIDE_BUY, OrderType: pb.OrderType_ORDER_TYPE_LIMIT, Amount: 14, Price: 101})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.CancelOrder(ctx, &pb.CancelRequest{UserId: 1, Symbol: testSymbol, OrderId: ids[2]}); err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := engine.GetOrder(ctx, &pb.GetOrderRequest{OrderId: buy.OrderId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.State != pb.OrderState_ORDER_STATE_FILLED || order.OriginalAmount != 14 || order.RemainingAmount != 0 ||
+		order.AveragePrice != (10*100+4*101)/14.0 || order.CreatedTime == 0 || order.UpdatedTime < order.CreatedTime {
+		t.Errorf("Expected the buy to be filled, but got %v", order)
+	}
+	if order, err := engine.GetOrder(ctx, &pb.GetOrderRequest{OrderId: ids[2]}); err != nil || order.State != pb.OrderState_ORDER_STATE_CANCELLED {
+		t.Errorf("Expected order %d to be cancelled, but got %v, %v", ids[2], order, err)
+	}
+	if _, err := engine.GetOrder(ctx, &pb.GetOrderRequest{OrderId: 1000}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected unknown order to be not found, but got %v", err)
+	}
+
+	open, err := engine.ListOpenOrders(ctx, &pb.ListOpenOrdersRequest{UserId: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open.Orders) != 1 || open.Orders[0].OrderId != ids[1] || open.Orders[0].RemainingAmount != 6 ||
+		open.Orders[0].State != pb.OrderState_ORDER_STATE_PARTIALLY_FILLED {
+		t.Errorf("Expected only order %d with 6 left to be open, but got %v", ids[1], open.Orders)
+	}
+}
+
+func TestMarketOrderIgnoresPrice(t *testing.T) {
+	m := newTestMarket(t)
+	for i, price := range []int64{100, 101, 102} {
+		m.sellBook.Add(orderbook.Order{Id: uint64(i), UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: price})
+	}
+
+	result := processOrder(m, orderbook.Order{Id: 10, UserID: 2, Type: "BUY", OrderType: "MARKET", Amount: 25})
+	if result.status != pb.OrderStatus_ORDER_STATUS_FILLED || len(result.matches) != 3 {
+		t.Errorf("Expected market buy without price to sweep 3 levels, but got %v %v", result.status, result.matches)
+	}
+
+	m.buyBook.Add(orderbook.Order{Id: 11, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 90})
+	result = processOrder(m, orderbook.Order{Id: 12, UserID: 2, Type: "SELL", OrderType: "MARKET", Amount: 5, Price: 1000})
+	if result.status != pb.OrderStatus_ORDER_STATUS_FILLED {
+		t.Errorf("Expected market sell with a high price to trade, but got %v", result.status)
+	}
+}
+
+func TestMarketOrderProtection(t *testing.T) {
+	m := newTestMarket(t)
+	for i, price := range []int64{100, 101, 103} {
+		m.sellBook.Add(orderbook.Order{Id: uint64(i), UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: price})
+	}
+
+	result := processOrder(m, orderbook.Order{Id: 10, UserID: 2, Type: "BUY", OrderType: "MARKET", Amount: 30, MaxSlippageTicks: 2})
+	if result.status != pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED || len(result.matches) != 2 || result.order.Amount != 10 {
+		t.Errorf("Expected to stop 2 ticks from 100 with 10 unfilled, but got %v %v", result.status, result.matches)
+	}
+	if result.message != "protection limit reached" {
+		t.Errorf("Expected the protection limit as reason, but got %q", result.message)
+	}
+
+	// The last trade was at 101, so a collar of 1 tick keeps the market buy away from 103.
+	m.collarTicks = 1
+	result = processOrder(m, orderbook.Order{Id: 11, UserID: 2, Type: "BUY", OrderType: "MARKET", Amount: 5})
+	if len(result.matches) != 0 || result.order.Amount != 5 {
+		t.Errorf("Expected the collar to prevent trading at 103, but got %v", result.matches)
+	}
+}
+
+func TestImmediateOrCancel(t *testing.T) {
+	m := newTestMarket(t)
+	m.sellBook.Add(orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 100})
+
+	result := processOrder(m, orderbook.Order{Id: 2, UserID: 2, Type: "BUY", OrderType: "LIMIT", TimeInForce: orderbook.IOC, Amount: 15, Price: 100})
+	if result.status != pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED || result.order.Amount != 5 || len(result.matches) != 1 {
+		t.Errorf("Expected 10 to execute and 5 to be cancelled, but got %v %v", result.status, result.order)
+	}
+	if m.buyBook.Len() != 0 {
+		t.Errorf("Expected the remainder of the IOC order to not rest.")
+	}
+}
+
+func TestFillOrKill(t *testing.T) {
+	m := newTestMarket(t)
+	m.sellBook.Add(orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 100})
+	m.sellBook.Add(orderbook.Order{Id: 2, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 102})
+
+	result := processOrder(m, orderbook.Order{Id: 3, UserID: 2, Type: "BUY", OrderType: "LIMIT", TimeInForce: orderbook.FOK, Amount: 15, Price: 101})
+	if result.status != pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED || len(result.matches) != 0 {
+		t.Errorf("Expected FOK order that can't fill to be killed, but got %v %v", result.status, result.matches)
+	}
+	if top, _ := m.sellBook.Peek(); top.Amount != 10 || m.sellBook.Len() != 2 {
+		t.Errorf("Expected the books to be untouched, but got %v", top)
+	}
+
+	result = processOrder(m, orderbook.Order{Id: 4, UserID: 2, Type: "BUY", OrderType: "LIMIT", TimeInForce: orderbook.FOK, Amount: 15, Price: 102})
+	if result.status != pb.OrderStatus_ORDER_STATUS_FILLED {
+		t.Errorf("Expected FOK order to fill across two levels, but got %v", result.status)
+	}
+}
+
+func TestFillOrKillIgnoresOwnOrders(t *testing.T) {
+	m := newTestMarket(t)
+	m.sellBook.Add(orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: 100})
+	m.sellBook.Add(orderbook.Order{Id: 2, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: 100})
+
+	// Self-trade prevention removes the order of user 1 instead of trading with it, which leaves too little to fill.
+	result := processOrder(m, orderbook.Order{Id: 3, UserID: 1, Type: "BUY", OrderType: "LIMIT", TimeInForce: orderbook.FOK, Amount: 10, Price: 100, SelfTradePrevention: orderbook.CancelOldest})
+	if result.status != pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED || len(result.matches) != 0 {
+		t.Errorf("Expected FOK order to be killed, but got %v %v", result.status, result.matches)
+	}
+	if m.buyBook.Len() != 0 || m.sellBook.Len() != 2 {
+		t.Errorf("Expected the books to be untouched, but got %d buys and %d sells", m.buyBook.Len(), m.sellBook.Len())
+	}
+}
+
+func TestGoodTillDateExpires(t *testing.T) {
+	m := newTestMarket(t)
+	sub := &executionSubscriber{reports: make(chan *pb.ExecutionReport, 16)}
+	m.executions.subscribe(1, 0, sub)
+
+	expireTime := time.Now().Add(time.Hour).UnixNano()
+	order := orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", TimeInForce: orderbook.GTD, ExpireTime: expireTime, Amount: 10, Price: 100}
+	sequence(m, command{kind: newOrder, order: order})
+	sequence(m, command{kind: expireOrders})
+	if m.sellBook.Len() != 1 {
+		t.Fatalf("Expected the order to rest until it expires.")
+	}
+
+	processExpire(m, expireTime)
+	publishExecutions(m)
+	if m.sellBook.Len() != 0 || len(m.expiries) != 0 {
+		t.Errorf("Expected the order to be expired.")
+	}
+	<-sub.reports
+	if report := <-sub.reports; report.ExecType != pb.ExecType_EXEC_TYPE_EXPIRED || report.RemainingAmount != 10 {
+		t.Errorf("Expected an expiry report, but got %v", report)
+	}
+}
+
+func TestExpiredOrdersDontTrade(t *testing.T) {
+	m := newTestMarket(t)
+	expireTime := time.Now().Add(20 * time.Millisecond).UnixNano()
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", TimeInForce: orderbook.GTD, ExpireTime: expireTime, Amount: 10, Price: 100}})
+	time.Sleep(30 * time.Millisecond)
+
+	// No expiry check ran since the order expired, the incoming order expires it before matching.
+	result := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 2, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 100}})
+	if len(result.matches) != 0 || result.status != pb.OrderStatus_ORDER_STATUS_RESTING {
+		t.Errorf("Expected the buy order to rest instead of trading with the expired one, but got %v", result)
+	}
+	if m.sellBook.Len() != 0 {
+		t.Errorf("Expected the expired order to be removed.")
+	}
+}
+
+func TestSessionClose(t *testing.T) {
+	m := &market{sessionClose: 22 * time.Hour}
+	before := time.Date(2024, 3, 1, 21, 0, 0, 0, time.UTC)
+	if got := time.Unix(0, m.sessionCloseAfter(before.UnixNano())).UTC(); !got.Equal(time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected DAY order to expire the same day, but got %v", got)
+	}
+	after := time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)
+	if got := time.Unix(0, m.sessionCloseAfter(after.UnixNano())).UTC(); !got.Equal(time.Date(2024, 3, 2, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected DAY order to expire the next day, but got %v", got)
+	}
+}
+
+func TestStopOrdersCascade(t *testing.T) {
+	m := newTestMarket(t)
+	for i, price := range []int64{100, 101, 102} {
+		m.sellBook.Add(orderbook.Order{Id: uint64(i), UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: price})
+	}
+
+	stop := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 10, UserID: 2, Type: "BUY", OrderType: orderbook.Stop, TimeInForce: orderbook.IOC, Amount: 5, StopPrice: 101}})
+	stopLimit := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 11, UserID: 2, Type: "BUY", OrderType: orderbook.StopLimit, Amount: 5, Price: 102, StopPrice: 102}})
+	if stop.status != pb.OrderStatus_ORDER_STATUS_PENDING_TRIGGER || stopLimit.status != pb.OrderStatus_ORDER_STATUS_PENDING_TRIGGER {
+		t.Fatalf("Expected stop orders to wait for their trigger, but got %v and %v", stop.status, stopLimit.status)
+	}
+
+	// Trading at 101 releases the stop order, whose trade at 102 releases the stop limit order.
+	result := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 12, UserID: 3, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 101}})
+	if len(result.matches) != 2 || len(result.triggered) != 2 {
+		t.Fatalf("Expected 2 trades releasing 2 stop orders, but got %v and %d released", result.matches, len(result.triggered))
+	}
+	if released := result.triggered[0]; released.order.Id != 10 || len(released.matches) != 1 || released.matches[0].price != 102 {
+		t.Errorf("Expected stop order 10 to buy at 102, but got %v", released.matches)
+	}
+	if released := result.triggered[1]; released.order.Id != 11 || released.status != pb.OrderStatus_ORDER_STATUS_RESTING {
+		t.Errorf("Expected stop limit order 11 to rest, but got %v", released.status)
+	}
+	if top, ok := m.buyBook.Peek(); !ok || top.Id != 11 || top.Price != 102 {
+		t.Errorf("Expected order 11 to rest at 102, but got %v", top)
+	}
+	if m.buyStops.Len() != 0 {
+		t.Errorf("Expected no stop orders to be left.")
+	}
+}
+
+func TestCancelStopOrder(t *testing.T) {
+	m := newTestMarket(t)
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: orderbook.Stop, Amount: 5, StopPrice: 90}})
+
+	if result := sequence(m, command{kind: cancelOrder, orderId: 1, userId: 2}); result.success {
+		t.Errorf("Expected other users to not be able to cancel the stop order.")
+	}
+	if result := sequence(m, command{kind: cancelOrder, orderId: 1, userId: 1}); !result.success || m.sellStops.Len() != 0 {
+		t.Errorf("Expected stop order to be cancelled, but got %v", result.message)
+	}
+}
+
+func TestIcebergReplenishes(t *testing.T) {
+	m := newTestMarket(t)
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 100, DisplayAmount: 4}})
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: 100}})
+	if level := m.sellBook.Level(100); level.Amount != 9 {
+		t.Errorf("Expected only the displayed slice to be in the book, but got %d at 100", level.Amount)
+	}
+
+	// The next slice queues behind order 2.
+	result := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 3, UserID: 3, Type: "BUY", OrderType: "LIMIT", Amount: 6, Price: 100}})
+	if len(result.matches) != 2 || result.matches[0].sellId != 1 || result.matches[0].amount != 4 || result.matches[1].sellId != 2 {
+		t.Fatalf("Expected the slice and then order 2 to fill, but got %v", result.matches)
+	}
+	if level := m.sellBook.Level(100); level.Amount != 7 {
+		t.Errorf("Expected a new slice of 4 to be displayed, but got %d at 100", level.Amount)
+	}
+	if order, _ := m.orders.get(1); order.Remaining != 6 || order.Filled != 4 || order.State != pb.OrderState_ORDER_STATE_PARTIALLY_FILLED {
+		t.Errorf("Expected the iceberg to have 6 left after filling 4, but got %+v", order)
+	}
+
+	// Slices keep being replenished within one order until the reserve runs out.
+	result = sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 4, UserID: 3, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 100}})
+	if len(result.matches) != 3 || result.order.Amount != 1 {
+		t.Errorf("Expected 3 fills and 1 left resting, but got %v", result.matches)
+	}
+	if order, _ := m.orders.get(1); order.Remaining != 0 || order.Filled != 10 || order.State != pb.OrderState_ORDER_STATE_FILLED {
+		t.Errorf("Expected the iceberg to be filled, but got %+v", order)
+	}
+}
+
+func TestFillOrKillAgainstIceberg(t *testing.T) {
+	m := newTestMarket(t)
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 100, DisplayAmount: 2}})
+
+	result := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 2, Type: "BUY", OrderType: "LIMIT", TimeInForce: orderbook.FOK, Amount: 5, Price: 100}})
+	if result.status != pb.OrderStatus_ORDER_STATUS_FILLED || len(result.matches) != 3 {
+		t.Errorf("Expected FOK order to fill from the iceberg's slices, but got %v %v", result.status, result.matches)
+	}
+
+	// The next slices queue behind the order of user 3, which self-trade prevention stops at.
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 3, UserID: 3, Type: "SELL", OrderType: "LIMIT", Amount: 1, Price: 100}})
+	result = sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 4, UserID: 3, Type: "BUY", OrderType: "LIMIT", TimeInForce: orderbook.FOK, Amount: 3, Price: 100, SelfTradePrevention: orderbook.CancelNewest}})
+	if result.status != pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED || len(result.matches) != 0 {
+		t.Errorf("Expected FOK order to be killed, but got %v %v", result.status, result.matches)
+	}
+}
+
+func TestPostOnly(t *testing.T) {
+	m := newTestMarket(t)
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 100}})
+
+	result := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 2, Type: "BUY", OrderType: "LIMIT", Amount: 5, Price: 100, PostOnly: orderbook.PostOnlyReject}})
+	if result.status != pb.OrderStatus_ORDER_STATUS_REJECTED || result.rejectReason != pb.RejectReason_REJECT_REASON_WOULD_TAKE_LIQUIDITY {
+		t.Errorf("Expected crossing post-only order to be rejected, but got %v", result.status)
+	}
+
+	result = sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 3, UserID: 2, Type: "BUY", OrderType: "LIMIT", Amount: 5, Price: 105, PostOnly: orderbook.PostOnlyReprice}})
+	if result.status != pb.OrderStatus_ORDER_STATUS_RESTING || !result.repriced || result.order.Price != 99 || len(result.matches) != 0 {
+		t.Errorf("Expected crossing post-only order to rest at 99, but got %v at %d", result.status, result.order.Price)
+	}
+
+	result = sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 4, UserID: 2, Type: "BUY", OrderType: "LIMIT", Amount: 5, Price: 98, PostOnly: orderbook.PostOnlyReject}})
+	if result.status != pb.OrderStatus_ORDER_STATUS_RESTING || result.repriced || result.order.Price != 98 {
+		t.Errorf("Expected post-only order that doesn't cross to rest at its price, but got %v at %d", result.status, result.order.Price)
+	}
+	if top, _ := m.sellBook.Peek(); top.Amount != 10 {
+		t.Errorf("Expected post-only orders to not trade, but the best sell has %d left", top.Amount)
+	}
+
+	if result := sequence(m, command{kind: amendOrder, orderId: 4, userId: 2, price: 101}); result.success {
+		t.Errorf("Expected amending the post-only order to cross to fail.")
+	}
+}
+
+func TestSelfTradePrevention(t *testing.T) {
+	tests := []struct {
+		mode      orderbook.SelfTradePrevention
+		status    pb.OrderStatus
+		matched   int32 // Amount traded with order 2 of the other user.
+		sellsLeft int64
+		oldest    pb.OrderState
+	}{
+		{orderbook.CancelNewest, pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED, 0, 10, pb.OrderState_ORDER_STATE_NEW},
+		{orderbook.CancelOldest, pb.OrderStatus_ORDER_STATUS_PARTIALLY_FILLED, 5, 0, pb.OrderState_ORDER_STATE_CANCELLED},
+		{orderbook.CancelBoth, pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED, 0, 5, pb.OrderState_ORDER_STATE_CANCELLED},
+		{orderbook.DecrementAndCancel, pb.OrderStatus_ORDER_STATUS_FILLED, 3, 2, pb.OrderState_ORDER_STATE_CANCELLED},
+	}
+	for _, test := range tests {
+		m := newTestMarket(t)
+		sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: 100}})
+		sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: 100}})
+
+		result := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 3, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 8, Price: 100, SelfTradePrevention: test.mode}})
+		var matched int32
+		for _, match := range result.matches {
+			if match.sellId != 2 {
+				t.Errorf("%s: expected no trades with the user's own order, but got %v", test.mode, match)
+			}
+			matched += match.amount
+		}
+		if result.status != test.status || matched != test.matched {
+			t.Errorf("%s: expected %v with %d traded, but got %v with %d", test.mode, test.status, test.matched, result.status, matched)
+		}
+		if level := m.sellBook.Level(100); level.Amount != test.sellsLeft {
+			t.Errorf("%s: expected %d left to sell, but got %d", test.mode, test.sellsLeft, level.Amount)
+		}
+		if order, _ := m.orders.get(1); order.State != test.oldest {
+			t.Errorf("%s: expected the resting order to be %v, but got %v", test.mode, test.oldest, order.State)
+		}
+	}
+}
+
+func TestSelfTradeGroup(t *testing.T) {
+	m := newTestMarket(t)
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: 100, SelfTradeGroup: 7}})
+
+	result := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 2, Type: "BUY", OrderType: "LIMIT", Amount: 5, Price: 100, SelfTradePrevention: orderbook.CancelNewest, SelfTradeGroup: 7}})
+	if len(result.matches) != 0 || result.message != "self-trade prevention CANCEL_NEWEST" {
+		t.Errorf("Expected orders of the same group to not trade, but got %v: %s", result.matches, result.message)
+	}
+	result = sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 3, UserID: 3, Type: "BUY", OrderType: "LIMIT", Amount: 5, Price: 100, SelfTradePrevention: orderbook.CancelNewest, SelfTradeGroup: 8}})
+	if len(result.matches) != 1 {
+		t.Errorf("Expected orders of other groups to trade, but got %v", result.matches)
+	}
+}
+
+func TestGetOrderBook(t *testing.T) {
+	engine := newTestEngine(t)
+	go ProcessOrders(engine)
+
+	ctx := context.Background()
+	for _, order := range []struct {
+		side   pb.Side
+		amount int32
+		price  int64
+	}{{pb.Side_SIDE_BUY, 5, 99}, {pb.Side_SIDE_BUY, 3, 99}, {pb.Side_SIDE_BUY, 4, 98}, {pb.Side_SIDE_SELL, 7, 101}} {
+		if _, err := engine.SendOrder(ctx, &pb.OrderRequest{UserId: 1, Symbol: testSymbol, Side: order.side, OrderType: pb.OrderType_ORDER_TYPE_LIMIT, Amount: order.amount, Price: order.price}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	book, err := engine.GetOrderBook(ctx, &pb.OrderBookRequest{Symbol: testSymbol, Depth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if book.Seq != 4 || len(book.Bids) != 1 || book.Bids[0].Amount != 8 || book.Bids[0].OrderCount != 2 || len(book.Bids[0].Orders) != 0 {
+		t.Errorf("Expected the best bid level with 2 orders, but got %v", book)
+	}
+
+	book, err = engine.GetOrderBook(ctx, &pb.OrderBookRequest{Symbol: testSymbol, Level: pb.BookLevel_BOOK_LEVEL_L3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(book.Bids) != 2 || len(book.Asks) != 1 {
+		t.Fatalf("Expected all levels, but got %v", book)
+	}
+	if orders := book.Bids[0].Orders; len(orders) != 2 || orders[0].Amount != 5 || orders[1].Amount != 3 || orders[0].Time >= orders[1].Time {
+		t.Errorf("Expected the orders at 99 in time priority, but got %v", orders)
+	}
+
+	if _, err := engine.GetOrderBook(ctx, &pb.OrderBookRequest{Symbol: "NOPE"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected an unknown symbol to not be found, but got %v", err)
+	}
+}
+
+func TestTradeRecords(t *testing.T) {
+	instruments, err := instrument.NewRegistry(instrument.Instrument{Symbol: testSymbol})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring := reporter.NewRing(16)
+	engine, err := New(Config{QueueSize: 32, Instruments: instruments, DataDir: t.TempDir(), TradeSink: func(string) (reporter.Sink, error) { return ring, nil }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := engine.markets[testSymbol]
+
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 7, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 100}})
+	first := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 8, Type: "SELL", OrderType: "LIMIT", Amount: 4, Price: 95}})
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 3, UserID: 9, Type: "SELL", OrderType: "LIMIT", Amount: 4, Price: 95}})
+	sequence(m, command{kind: cancelOrder, orderId: 1, userId: 7})
+
+	records := ring.Records()
+	if len(records) != 3 || records[0].Trade == nil || records[1].Trade == nil || records[2].Cancel == nil {
+		t.Fatalf("Expected 2 trades and a cancel, but got %v", records)
+	}
+	expected := reporter.Trade{
+		TradeId: 1, Time: first.order.Time, Symbol: testSymbol, AggressorSide: "SELL",
+		TakerOrderId: 2, TakerUserId: 8, MakerOrderId: 1, MakerUserId: 7, Amount: 4, Price: 100,
+	}
+	if *records[0].Trade != expected {
+		t.Errorf("Expected trade record %+v, but got %+v", expected, *records[0].Trade)
+	}
+	if records[1].Trade.TradeId != 2 {
+		t.Errorf("Expected trade ids to increase by one, but got %d", records[1].Trade.TradeId)
+	}
+	if cancel := *records[2].Cancel; cancel.OrderId != 1 || cancel.Amount != 2 {
+		t.Errorf("Expected the remaining 2 of order 1 to be cancelled, but got %+v", cancel)
+	}
+}
+
+// balances returns the balances of userId by asset.
+func balances(a *accounts.Accounts, userId int32) map[string]accounts.Balance {
+	assets, list := a.Balances(userId)
+	balances := make(map[string]accounts.Balance, len(assets))
+	for i, asset := range assets {
+		balances[asset] = list[i]
+	}
+	return balances
+}
+
+func TestAccounts(t *testing.T) {
+	instruments, err := instrument.NewRegistry(instrument.Instrument{Symbol: testSymbol, TickSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{QueueSize: 32, Instruments: instruments, DataDir: t.TempDir()}
+	accountsFile, ledgerFile := filepath.Join(cfg.DataDir, "accounts.journal"), filepath.Join(cfg.DataDir, "ledger")
+	if cfg.Accounts, err = accounts.Open(accountsFile, ledgerFile); err != nil {
+		t.Fatal(err)
+	}
+	engine, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := engine.markets[testSymbol]
+	cfg.Accounts.Deposit(1, accounts.Cash, 1000)
+	cfg.Accounts.Deposit(2, testSymbol, 10)
+
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 100}})
+	rejected := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 1, Price: 1}})
+	if rejected.status != pb.OrderStatus_ORDER_STATUS_REJECTED || rejected.rejectReason != pb.RejectReason_REJECT_REASON_INSUFFICIENT_FUNDS {
+		t.Fatalf("Expected the order to be rejected for insufficient funds, but got %v", rejected)
+	}
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 3, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 4, Price: 90}})
+	if result := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 4, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 7, Price: 90}}); result.rejectReason != pb.RejectReason_REJECT_REASON_INSUFFICIENT_FUNDS {
+		t.Errorf("Expected selling more than is held to be rejected, but got %v", result)
+	}
+
+	expected := map[int32]map[string]accounts.Balance{
+		1: {accounts.Cash: {Total: 600, Reserved: 600}, testSymbol: {Total: 4}},
+		2: {accounts.Cash: {Total: 400}, testSymbol: {Total: 6}},
+	}
+	check := func(when string) {
+		for userId, want := range expected {
+			if got := balances(cfg.Accounts, userId); len(got) != len(want) || got[accounts.Cash] != want[accounts.Cash] || got[testSymbol] != want[testSymbol] {
+				t.Errorf("Expected user %d to have %v %s, but got %v", userId, want, when, got)
+			}
+		}
+	}
+	check("after the trade")
+
+	// MARKET orders can't spend more than what sweeping the book cost when they were sequenced.
+	sequence(m, command{kind: cancelOrder, orderId: 1, userId: 1})
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 5, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: 50}})
+	result := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 6, UserID: 1, Type: "BUY", OrderType: "MARKET", Amount: 10, TimeInForce: orderbook.IOC}})
+	if result.status != pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED || result.order.Filled != 5 {
+		t.Errorf("Expected the market order to fill 5, but got %v", result)
+	}
+	expected = map[int32]map[string]accounts.Balance{
+		1: {accounts.Cash: {Total: 350}, testSymbol: {Total: 9}},
+		2: {accounts.Cash: {Total: 650}, testSymbol: {Total: 1}},
+	}
+	check("after the market order")
+
+	takeSnapshot(engine, m)
+	waitForSnapshot(m)
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 7, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 1, Price: 100}})
+	engine.Close()
+	cfg.Accounts.Close()
+
+	// Deposits come from the journal of the accounts, trades and reservations from the snapshot and journal of the market.
+	if cfg.Accounts, err = accounts.Open(accountsFile, ledgerFile); err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected[1] = map[string]accounts.Balance{accounts.Cash: {Total: 350, Reserved: 100}, testSymbol: {Total: 9}}
+	check("after recovery")
+	recovered.closeFiles()
+	cfg.Accounts.Close()
+
+	// Trades replayed after the restart were posted before, so the ledger has each of them once.
+	report, err := ledger.Reconcile(ledgerFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Entries != 4 || len(report.Problems) != 0 {
+		t.Errorf("Expected 2 deposits and 2 trades to reconcile, but got %+v", report)
+	}
+	if cash := report.Balances[ledger.UserAccount(2)][accounts.Cash]; cash != 650 {
+		t.Errorf("Expected the ledger to have 650 cash for user 2, but got %d", cash)
+	}
+}
+
+func TestFees(t *testing.T) {
+	tiers := []instrument.FeeTier{{MinVolume: 0, MakerBps: -1, TakerBps: 10}, {MinVolume: 10000, MakerBps: -2, TakerBps: 5}}
+	instruments, err := instrument.NewRegistry(instrument.Instrument{Symbol: testSymbol, Fees: tiers})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring := reporter.NewRing(16)
+	cfg := Config{QueueSize: 32, Instruments: instruments, DataDir: t.TempDir(), TradeSink: func(string) (reporter.Sink, error) { return ring, nil }}
+	ledgerFile := filepath.Join(cfg.DataDir, "ledger")
+	if cfg.Accounts, err = accounts.Open(filepath.Join(cfg.DataDir, "accounts.journal"), ledgerFile); err != nil {
+		t.Fatal(err)
+	}
+	engine, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := engine.markets[testSymbol]
+	cfg.Accounts.Deposit(1, accounts.Cash, 100000)
+	cfg.Accounts.Deposit(2, testSymbol, 100)
+
+	// Buy orders reserve the highest fee of the schedule on top of their cost.
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 1000}})
+	if got := balances(cfg.Accounts, 1)[accounts.Cash]; got.Reserved != 10010 {
+		t.Errorf("Expected 10010 to be reserved, but got %+v", got)
+	}
+
+	// Both start in the first tier, the maker gets a rebate of 1 bps and the taker pays 10.
+	result := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 1000}})
+	if fills := fills(result.matches); len(fills) != 1 || fills[0].Fee != 10 {
+		t.Errorf("Expected the taker to pay a fee of 10, but got %v", fills)
+	}
+	if order, _ := m.orders.get(1); order.Fees != -1 {
+		t.Errorf("Expected the maker to get a rebate of 1, but got %d", order.Fees)
+	}
+
+	// Having traded 10000, both are in the second tier.
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 3, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 1000}})
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 4, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 1000}})
+	records := ring.Records()
+	if len(records) != 2 || records[1].Trade == nil || records[1].Trade.MakerFee != -2 || records[1].Trade.TakerFee != 5 {
+		t.Errorf("Expected the second trade to record fees of -2 and 5, but got %v", records)
+	}
+
+	// MARKET orders budget for the fee too, and spend all of it.
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 5, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: 1000}})
+	if result := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 6, UserID: 1, Type: "BUY", OrderType: "MARKET", Amount: 5}}); result.order.Filled != 5 {
+		t.Errorf("Expected the market order to fill 5, but got %v", result)
+	}
+
+	expected := map[int32]accounts.Balance{1: {Total: 100000 - 25000 + 1 + 2 - 2}, 2: {Total: 25000 - 10 - 5 + 1}}
+	for userId, want := range expected {
+		if got := balances(cfg.Accounts, userId)[accounts.Cash]; got != want {
+			t.Errorf("Expected user %d to have %+v cash, but got %+v", userId, want, got)
+		}
+	}
+	takeSnapshot(engine, m)
+	waitForSnapshot(m)
+	engine.Close()
+	cfg.Accounts.Close()
+
+	// The volumes setting the tiers are restored from the snapshot.
+	if cfg.Accounts, err = accounts.Open(filepath.Join(cfg.DataDir, "accounts.journal"), ledgerFile); err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if volume := recovered.markets[testSymbol].fees.volume(1, m.lastTime/day); volume != 25000 {
+		t.Errorf("Expected user 1 to have traded 25000 after recovery, but got %d", volume)
+	}
+	recovered.closeFiles()
+	cfg.Accounts.Close()
+
+	report, err := ledger.Reconcile(ledgerFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Problems) != 0 || report.Balances[ledger.Fees][accounts.Cash] != 9+3+1 {
+		t.Errorf("Expected the fees account to hold 13, but got %+v", report)
+	}
+}
+
+func TestFeeWindow(t *testing.T) {
+	s := newFeeSchedule([]instrument.FeeTier{{MinVolume: 0, TakerBps: 10}, {MinVolume: 10000, TakerBps: 5}})
+	s.charge(&Match{aggressor: orderbook.Buy, buyUserId: 1, sellUserId: 2, amount: 10, price: 1000})
+
+	if tier := s.tier(1, 29); tier.TakerBps != 5 {
+		t.Errorf("Expected the volume to count for 30 days, but got %+v", tier)
+	}
+	if tier := s.tier(1, 30); tier.TakerBps != 10 {
+		t.Errorf("Expected the volume to stop counting after 30 days, but got %+v", tier)
+	}
+	if len(s.snapshot(30)) != 0 {
+		t.Errorf("Expected the snapshot to leave out volume outside the window, but got %v", s.snapshot(30))
+	}
+}
+
+func TestFundsAreReservedInBatchOrder(t *testing.T) {
+	instruments, err := instrument.NewRegistry(instrument.Instrument{Symbol: testSymbol})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{QueueSize: 32, Instruments: instruments, DataDir: t.TempDir()}
+	if cfg.Accounts, err = accounts.Open(filepath.Join(cfg.DataDir, "accounts.journal"), filepath.Join(cfg.DataDir, "ledger")); err != nil {
+		t.Fatal(err)
+	}
+	defer cfg.Accounts.Close()
+	engine, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer engine.closeFiles()
+	m := engine.markets[testSymbol]
+	cfg.Accounts.Deposit(1, accounts.Cash, 1000)
+
+	// The amendment is checked against the order placed before it in the same batch, and the order after the cancel
+	// against the funds the cancel released.
+	batch := []command{
+		{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 100}},
+		{kind: amendOrder, orderId: 1, userId: 1, amount: 1000},
+		{kind: cancelOrder, orderId: 1, userId: 1},
+		{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 100}},
+	}
+	for i := range batch {
+		batch[i].symbol, batch[i].resultChan = m.symbol, make(chan result, 1)
+	}
+	processBatch(m, batch)
+	results := make([]result, len(batch))
+	for i, cmd := range batch {
+		results[i] = <-cmd.resultChan
+	}
+	if results[1].success {
+		t.Errorf("Expected the amendment to be unfunded, but got %v", results[1])
+	}
+	if !results[2].success || results[3].status != pb.OrderStatus_ORDER_STATUS_RESTING {
+		t.Errorf("Expected the cancel to fund the next order, but got %v and %v", results[2], results[3])
+	}
+	if got := balances(cfg.Accounts, 1)[accounts.Cash]; got != (accounts.Balance{Total: 1000, Reserved: 1000}) {
+		t.Errorf("Expected 1000 to be reserved, but got %+v", got)
+	}
+}
+
+func TestFillOrKillWithinBudget(t *testing.T) {
+	instruments, err := instrument.NewRegistry(instrument.Instrument{Symbol: testSymbol})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{QueueSize: 32, Instruments: instruments, DataDir: t.TempDir()}
+	if cfg.Accounts, err = accounts.Open(filepath.Join(cfg.DataDir, "accounts.journal"), filepath.Join(cfg.DataDir, "ledger")); err != nil {
+		t.Fatal(err)
+	}
+	defer cfg.Accounts.Close()
+	engine, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer engine.closeFiles()
+	m := engine.markets[testSymbol]
+	cfg.Accounts.Deposit(1, accounts.Cash, 10000)
+	cfg.Accounts.Deposit(2, testSymbol, 21)
+	cfg.Accounts.Deposit(3, accounts.Cash, 1000)
+
+	unprotected := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 1, UserID: 1, Type: "BUY", OrderType: orderbook.Stop, Amount: 10, StopPrice: 100}})
+	if unprotected.status != pb.OrderStatus_ORDER_STATUS_REJECTED || unprotected.rejectReason != pb.RejectReason_REJECT_REASON_INVALID_SLIPPAGE {
+		t.Errorf("Expected a STOP buy without a protection limit to be rejected, but got %v", unprotected)
+	}
+
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 2, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 1, Price: 100}})
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 3, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 20, Price: 150}})
+	// The budget covers 10 at 120, which is 8 at 150 once the trade at 100 takes the only ask below it.
+	sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 4, UserID: 1, Type: "BUY", OrderType: orderbook.Stop, TimeInForce: orderbook.FOK, Amount: 10, StopPrice: 100, MaxSlippageTicks: 20}})
+	result := sequence(m, command{kind: newOrder, order: orderbook.Order{Id: 5, UserID: 3, Type: "BUY", OrderType: "LIMIT", Amount: 1, Price: 100}})
+	if len(result.triggered) != 1 {
+		t.Fatalf("Expected the stop order to be triggered, but got %v", result)
+	}
+	if killed := result.triggered[0]; killed.status != pb.OrderStatus_ORDER_STATUS_REMAINDER_CANCELLED || len(killed.matches) != 0 {
+		t.Errorf("Expected the fill or kill order to be killed without trading, but got %v", killed)
+	}
+	if got := balances(cfg.Accounts, 1)[accounts.Cash]; got != (accounts.Balance{Total: 10000}) {
+		t.Errorf("Expected the budget to be released, but got %+v", got)
+	}
+}
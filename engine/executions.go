@@ -0,0 +1,195 @@
+package engine
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MichalPitr/exchange/orderbook"
+	pb "github.com/MichalPitr/exchange/protos"
+)
+
+// Number of recent execution reports kept per user, which bounds how far back a reconnecting client can resume.
+const executionHistorySize = 4096
+
+type executionSubscriber struct {
+	reports chan *pb.ExecutionReport // Closed by the hub when the subscriber is dropped.
+	gone    atomic.Bool              // Set once the RPC handler stops reading.
+}
+
+type userExecutions struct {
+	seq         uint64
+	history     []*pb.ExecutionReport // The most recent reports, oldest first.
+	subscribers []*executionSubscriber
+}
+
+// executionHub sequences the execution reports of every user and fans them out to the user's subscribers. Users trade
+// in several markets, so unlike the market data feed it's shared by all market goroutines.
+type executionHub struct {
+	mutex sync.Mutex
+	users map[int32]*userExecutions
+}
+
+func newExecutionHub() *executionHub {
+	return &executionHub{users: make(map[int32]*userExecutions)}
+}
+
+func (h *executionHub) user(userId int32) *userExecutions {
+	user, ok := h.users[userId]
+	if !ok {
+		user = &userExecutions{}
+		h.users[userId] = user
+	}
+	return user
+}
+
+func (h *executionHub) publish(reports []*pb.ExecutionReport) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for _, report := range reports {
+		user := h.user(report.UserId)
+		user.seq++
+		report.Seq = user.seq
+		if len(user.history) == executionHistorySize {
+			copy(user.history, user.history[1:])
+			user.history = user.history[:executionHistorySize-1]
+		}
+		user.history = append(user.history, report)
+
+		active := user.subscribers[:0]
+		for _, sub := range user.subscribers {
+			if sub.gone.Load() {
+				continue
+			}
+			select {
+			case sub.reports <- report:
+				active = append(active, sub)
+			default:
+				close(sub.reports)
+			}
+		}
+		clear(user.subscribers[len(active):])
+		user.subscribers = active
+	}
+}
+
+// restore continues the sequence of every user after the reports that the markets recovered, which were sent before a
+// restart. Those reports aren't retained, so resuming from before the restart fails rather than reusing their numbers.
+func (h *executionHub) restore(counts map[int32]uint64) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for userId, count := range counts {
+		h.user(userId).seq += count
+	}
+}
+
+// subscribe registers sub for the future reports of userId and returns the retained reports from fromSeq on, so that
+// together they cover every report exactly once.
+func (h *executionHub) subscribe(userId int32, fromSeq uint64, sub *executionSubscriber) ([]*pb.ExecutionReport, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	user := h.user(userId)
+	if fromSeq > user.seq+1 {
+		return nil, fmt.Errorf("report %d wasn't sent yet, the last one is %d", fromSeq, user.seq)
+	}
+	var missed []*pb.ExecutionReport
+	if fromSeq > 0 && fromSeq <= user.seq {
+		oldest := user.seq - uint64(len(user.history)) + 1
+		if fromSeq < oldest {
+			return nil, fmt.Errorf("reports before %d are no longer available", oldest)
+		}
+		missed = append(missed, user.history[fromSeq-oldest:]...)
+	}
+	user.subscribers = append(user.subscribers, sub)
+	return missed, nil
+}
+
+func (e *Engine) SubscribeExecutions(in *pb.ExecutionsRequest, stream pb.OrderService_SubscribeExecutionsServer) error {
+	sub := &executionSubscriber{reports: make(chan *pb.ExecutionReport, subscriberBufferSize)}
+	defer sub.gone.Store(true)
+
+	missed, err := e.executions.subscribe(in.UserId, in.FromSeq, sub)
+	if err != nil {
+		return status.Error(codes.OutOfRange, err.Error())
+	}
+	for _, report := range missed {
+		if err := stream.Send(report); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case report, ok := <-sub.reports:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "Subscriber fell behind, resubscribe from the last report received")
+			}
+			if err := stream.Send(report); err != nil {
+				return err
+			}
+		case <-e.subscriptionsEnded:
+			return status.Error(codes.Unavailable, "Exchange is shutting down")
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		}
+	}
+}
+
+// report records what happened to order, which has to reflect the state after the event. Reports are published once
+// the command that caused them is processed.
+func (m *market) report(order orderbook.Order, execType pb.ExecType, reason string) *pb.ExecutionReport {
+	report := &pb.ExecutionReport{
+		UserId:           order.UserID,
+		OrderId:          order.Id,
+		Symbol:           m.symbol,
+		ExecType:         execType,
+		Side:             sideToProto(order.Type),
+		Price:            order.Price,
+		RemainingAmount:  order.Amount + order.Hidden,
+		CumulativeAmount: order.Filled,
+		Reason:           reason,
+		Time:             m.commandTime,
+		OrderType:        orderTypeToProto(order.OrderType),
+		StopPrice:        order.StopPrice,
+		DisplayAmount:    order.DisplayAmount,
+	}
+	m.reports = append(m.reports, report)
+	updateHold(m, order, execType)
+	return report
+}
+
+// reportFill records a fill of amount at price that cost order fee, order being the state after it.
+func (m *market) reportFill(order orderbook.Order, amount int32, price, fee int64, maker bool) {
+	execType := pb.ExecType_EXEC_TYPE_PARTIAL_FILL
+	if order.Amount+order.Hidden == 0 {
+		execType = pb.ExecType_EXEC_TYPE_FILL
+	}
+	report := m.report(order, execType, "")
+	report.LastAmount = amount
+	report.LastPrice = price
+	report.Fee = fee
+	report.Maker = maker
+}
+
+// publishExecutions updates the order registry with the reports of a processed command and hands them to the hub. While
+// replaying only the registry is updated and the reports are counted, subscribers got them when the command was first
+// applied.
+func publishExecutions(m *market) {
+	if len(m.reports) == 0 {
+		return
+	}
+	for _, report := range m.reports {
+		m.reportCounts[report.UserId]++
+	}
+	m.orders.record(m.reports)
+	if !m.replaying {
+		m.executions.publish(m.reports)
+	}
+	m.reports = nil
+}
@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"container/heap"
+	"fmt"
+	"time"
+
+	"github.com/MichalPitr/exchange/orderbook"
+	pb "github.com/MichalPitr/exchange/protos"
+)
+
+// How often the matching loop checks for orders that expired.
+const expiryCheckInterval = 100 * time.Millisecond
+
+type expiry struct {
+	time    int64
+	orderId uint64
+}
+
+// expiryQueue is a min-heap of the expiry times of resting orders. Orders that leave the book before they expire are
+// not removed from it, their entries are skipped once they come up.
+type expiryQueue []expiry
+
+func (q expiryQueue) Len() int           { return len(q) }
+func (q expiryQueue) Less(i, j int) bool { return q[i].time < q[j].time }
+func (q expiryQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *expiryQueue) Push(x any)        { *q = append(*q, x.(expiry)) }
+func (q *expiryQueue) Pop() any {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
+
+// due tells whether an order expires at or before t.
+func (q expiryQueue) due(t int64) bool {
+	return len(q) > 0 && q[0].time <= t
+}
+
+// rest adds a LIMIT order to its book, and keeps track of when it expires.
+func rest(m *market, order orderbook.Order) {
+	if order.Type == orderbook.Buy {
+		m.buyBook.Add(order)
+	} else {
+		m.sellBook.Add(order)
+	}
+	if order.ExpireTime != 0 {
+		heap.Push(&m.expiries, expiry{order.ExpireTime, order.Id})
+	}
+}
+
+// processExpire removes the orders that expired at or before timestamp.
+func processExpire(m *market, timestamp int64) result {
+	expired := 0
+	for m.expiries.due(timestamp) {
+		next := heap.Pop(&m.expiries).(expiry)
+		book := m.buyBook
+		order, ok := book.Get(next.orderId)
+		if !ok {
+			book = m.sellBook
+			order, ok = book.Get(next.orderId)
+		}
+		if !ok || order.ExpireTime != next.time {
+			continue
+		}
+
+		removed, _ := book.Remove(next.orderId)
+		m.report(removed, pb.ExecType_EXEC_TYPE_EXPIRED, "expired")
+		m.writeCancel(cancelRecord(m.symbol, removed))
+		expired++
+	}
+	if !m.replaying && expired > 0 {
+		m.reporter.Flush()
+	}
+	return result{success: true, message: fmt.Sprintf("%d orders expired", expired)}
+}
+
+// sessionCloseAfter returns the first close of a trading session after timestamp, which is when DAY orders placed at
+// timestamp expire.
+func (m *market) sessionCloseAfter(timestamp int64) int64 {
+	t := time.Unix(0, timestamp).UTC()
+	closing := t.Truncate(24 * time.Hour).Add(m.sessionClose)
+	if !closing.After(t) {
+		closing = closing.Add(24 * time.Hour)
+	}
+	return closing.UnixNano()
+}
@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/MichalPitr/exchange/instrument"
+	"github.com/MichalPitr/exchange/orderbook"
+)
+
+// The fee tier of a user is set by the notional they traded in the market over this many days, the current one
+// included. Days start at midnight UTC.
+const (
+	feeWindowDays = 30
+	day           = int64(24 * time.Hour)
+)
+
+// dayVolume is the notional a user traded on a day, counted in days since the epoch.
+type dayVolume struct {
+	Day    int64 `json:"day"`
+	Volume int64 `json:"volume"`
+}
+
+// feeSchedule charges the fees of a market. Its volumes are part of the market state, so that replaying a trade charges
+// the same fees as when it first happened.
+type feeSchedule struct {
+	tiers   []instrument.FeeTier
+	maxBps  int64                 // Highest rate a trade can pay, which buy orders reserve on top of their cost.
+	volumes map[int32][]dayVolume // Volume of every user by day, oldest first.
+}
+
+func newFeeSchedule(tiers []instrument.FeeTier) *feeSchedule {
+	s := &feeSchedule{tiers: tiers, volumes: make(map[int32][]dayVolume)}
+	for _, tier := range tiers {
+		s.maxBps = max(s.maxBps, tier.MakerBps, tier.TakerBps)
+	}
+	return s
+}
+
+// charge sets the fees of trade by the tiers its maker and taker were in before it, and adds it to their volume.
+func (s *feeSchedule) charge(trade *Match) {
+	if len(s.tiers) == 0 {
+		return
+	}
+	today := trade.time / day
+	notional := int64(trade.amount) * trade.price
+	makerId, takerId := trade.buyUserId, trade.sellUserId
+	if trade.aggressor == orderbook.Buy {
+		makerId, takerId = takerId, makerId
+	}
+	trade.makerFee = fee(notional, s.tier(makerId, today).MakerBps)
+	trade.takerFee = fee(notional, s.tier(takerId, today).TakerBps)
+	s.add(makerId, today, notional)
+	if takerId != makerId {
+		s.add(takerId, today, notional)
+	}
+}
+
+// fee returns bps of notional, rounded towards zero so that neither fees nor rebates are rounded up.
+func fee(notional, bps int64) int64 {
+	return notional * bps / instrument.BasisPoints
+}
+
+// reserve returns what a buy order reserves for the fees of trading cost, which covers any tier.
+func (s *feeSchedule) reserve(cost int64) int64 {
+	return (cost*s.maxBps + instrument.BasisPoints - 1) / instrument.BasisPoints
+}
+
+// affordable returns how much can be bought at price out of budget, leaving enough for the fees.
+func (s *feeSchedule) affordable(budget, price int64) int64 {
+	amount := budget * instrument.BasisPoints / (price * (instrument.BasisPoints + s.maxBps))
+	for amount > 0 && amount*price+s.reserve(amount*price) > budget {
+		amount--
+	}
+	return amount
+}
+
+// tier returns the tier userId is in on today.
+func (s *feeSchedule) tier(userId int32, today int64) instrument.FeeTier {
+	volume := s.volume(userId, today)
+	tier := s.tiers[0]
+	for _, t := range s.tiers[1:] {
+		if volume >= t.MinVolume {
+			tier = t
+		}
+	}
+	return tier
+}
+
+// volume returns what userId traded within the window ending on today, and forgets the days before it.
+func (s *feeSchedule) volume(userId int32, today int64) int64 {
+	days := s.volumes[userId]
+	for len(days) > 0 && days[0].Day <= today-feeWindowDays {
+		days = days[1:]
+	}
+	if len(days) == 0 {
+		delete(s.volumes, userId)
+		return 0
+	}
+	s.volumes[userId] = days
+	var volume int64
+	for _, d := range days {
+		volume += d.Volume
+	}
+	return volume
+}
+
+func (s *feeSchedule) add(userId int32, today, notional int64) {
+	days := s.volumes[userId]
+	if n := len(days); n > 0 && days[n-1].Day == today {
+		days[n-1].Volume += notional
+		return
+	}
+	s.volumes[userId] = append(days, dayVolume{Day: today, Volume: notional})
+}
+
+// snapshot returns a copy of the volumes within the window ending on today.
+func (s *feeSchedule) snapshot(today int64) map[int32][]dayVolume {
+	volumes := make(map[int32][]dayVolume, len(s.volumes))
+	for userId := range s.volumes {
+		if s.volume(userId, today) > 0 {
+			volumes[userId] = append([]dayVolume(nil), s.volumes[userId]...)
+		}
+	}
+	return volumes
+}
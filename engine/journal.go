@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MichalPitr/exchange/journal"
+	"github.com/MichalPitr/exchange/orderbook"
+)
+
+// journalEntry is the persisted form of a sequenced command. It holds everything apply needs to reproduce the command's
+// effect on the books, including the timestamp the market assigned.
+type journalEntry struct {
+	Seq     uint64           `json:"seq"`
+	Kind    commandType      `json:"kind"`
+	Time    int64            `json:"time"`
+	Order   *orderbook.Order `json:"order,omitempty"`
+	OrderId uint64           `json:"orderId,omitempty"`
+	UserId  int32            `json:"userId,omitempty"`
+	Price   int64            `json:"price,omitempty"`
+	Amount  int32            `json:"amount,omitempty"`
+
+	Unfunded bool  `json:"unfunded,omitempty"`
+	Reserved int64 `json:"reserved,omitempty"`
+}
+
+func appendToJournal(j *journal.Journal, cmd command) error {
+	entry := journalEntry{
+		Seq:     cmd.seq,
+		Kind:    cmd.kind,
+		Time:    cmd.time,
+		OrderId: cmd.orderId,
+		UserId:  cmd.userId,
+		Price:   cmd.price,
+		Amount:  cmd.amount,
+
+		Unfunded: cmd.unfunded,
+		Reserved: cmd.reserved,
+	}
+	if cmd.kind == newOrder {
+		entry.Order = &cmd.order
+	}
+	record, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return j.Append(record)
+}
+
+// The journal of a market is split into segments named after the sequence number of their first command. A new
+// segment is started with every snapshot, so that segments covered by snapshots can be deleted.
+func segmentName(dataDir, symbol string, firstSeq uint64) string {
+	return filepath.Join(dataDir, fmt.Sprintf("%s-%020d.journal", symbol, firstSeq))
+}
+
+type sequencedFile struct {
+	seq      uint64
+	filename string
+}
+
+// listSequenced returns the files of a market named <symbol>-<seq><ext>, ordered by sequence number.
+func listSequenced(dataDir, symbol, ext string) ([]sequencedFile, error) {
+	filenames, err := filepath.Glob(filepath.Join(dataDir, symbol+"-*"+ext))
+	if err != nil {
+		return nil, err
+	}
+	files := make([]sequencedFile, 0, len(filenames))
+	for _, filename := range filenames {
+		digits := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(filename), symbol+"-"), ext)
+		seq, err := strconv.ParseUint(digits, 10, 64)
+		if err != nil {
+			continue // Belongs to a symbol that shares our prefix.
+		}
+		files = append(files, sequencedFile{seq, filename})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].seq < files[j].seq })
+	return files, nil
+}
+
+// recoverMarket restores m from its latest valid snapshot and applies the journaled commands that came after it.
+// Nothing is reported while replaying, as it was reported when the commands were first applied. Afterwards the last
+// journal segment is opened for appending.
+func recoverMarket(e *Engine, m *market) error {
+	m.replaying = true
+	defer func() { m.replaying = false }()
+
+	if err := loadLatestSnapshot(e, m); err != nil {
+		return err
+	}
+
+	segments, err := listSequenced(m.dataDir, m.symbol, ".journal")
+	if err != nil {
+		return err
+	}
+	for i, segment := range segments {
+		if i+1 < len(segments) && segments[i+1].seq <= m.seq+1 {
+			continue // The snapshot already covers the whole segment.
+		}
+		_, err := journal.Replay(segment.filename, func(record []byte) error {
+			var entry journalEntry
+			if err := json.Unmarshal(record, &entry); err != nil {
+				return err
+			}
+			if entry.Seq <= m.seq {
+				return nil
+			}
+			if entry.Seq != m.seq+1 {
+				return fmt.Errorf("commands %d to %d are missing", m.seq+1, entry.Seq-1)
+			}
+			replay(e, m, entry)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", segment.filename, err)
+		}
+	}
+
+	// Market data starts from a snapshot for every subscriber, so there's no point in publishing what changed so far.
+	m.buyBook.TakeChanges()
+	m.sellBook.TakeChanges()
+
+	filename := segmentName(m.dataDir, m.symbol, m.seq+1)
+	if len(segments) > 0 {
+		last := segments[len(segments)-1]
+		if last.seq > m.seq+1 {
+			return fmt.Errorf("commands %d to %d are missing", m.seq+1, last.seq-1)
+		}
+		filename = last.filename
+	}
+	m.journal, err = journal.Open(filename)
+	return err
+}
+
+func replay(e *Engine, m *market, entry journalEntry) {
+	cmd := command{
+		kind:    entry.Kind,
+		symbol:  m.symbol,
+		seq:     entry.Seq,
+		time:    entry.Time,
+		orderId: entry.OrderId,
+		userId:  entry.UserId,
+		price:   entry.Price,
+		amount:  entry.Amount,
+
+		unfunded: entry.Unfunded,
+		reserved: entry.Reserved,
+	}
+	if entry.Order != nil {
+		cmd.order = *entry.Order
+		// Order ids are handed out before orders reach their market, so continue after the highest one seen.
+		raiseNextOrderId(e, cmd.order.Id+1)
+	}
+	m.seq = cmd.seq
+	m.lastTime = max(m.lastTime, cmd.time)
+	apply(m, cmd)
+	publishExecutions(m)
+}
+
+func raiseNextOrderId(e *Engine, id uint64) {
+	if id > e.nextOrderId.Load() {
+		e.nextOrderId.Store(id)
+	}
+}
+
+// rotateJournal closes the current journal segment and starts a new one with the next command.
+func rotateJournal(m *market) error {
+	if err := m.journal.Close(); err != nil {
+		return err
+	}
+	var err error
+	m.journal, err = journal.Open(segmentName(m.dataDir, m.symbol, m.seq+1))
+	return err
+}
@@ -0,0 +1,209 @@
+package engine
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MichalPitr/exchange/orderbook"
+	pb "github.com/MichalPitr/exchange/protos"
+)
+
+// Capacity of a subscriber's buffer. Subscribers that fall this far behind are dropped instead of slowing down the
+// matching loop.
+const subscriberBufferSize = 4096
+
+type subscriber struct {
+	depth   int                       // Levels per side, 0 for all of them.
+	updates chan *pb.MarketDataUpdate // Closed by the feed when the subscriber is dropped.
+	gone    atomic.Bool               // Set once the RPC handler stops reading.
+}
+
+// feed publishes the market data of a market to the subscribers that follow the same depth. It's only used from the
+// market's goroutine.
+type feed struct {
+	depth       int
+	seq         uint64
+	subscribers []*subscriber
+	bids, asks  []int64 // Prices of the levels the subscribers see, kept only for feeds limited to a depth.
+}
+
+func (f *feed) publish(symbol string, update *pb.MarketDataUpdate) {
+	f.seq++
+	update.Seq = f.seq
+	update.Symbol = symbol
+
+	active := f.subscribers[:0]
+	for _, sub := range f.subscribers {
+		if sub.gone.Load() {
+			continue
+		}
+		select {
+		case sub.updates <- update:
+			active = append(active, sub)
+		default:
+			close(sub.updates)
+		}
+	}
+	clear(f.subscribers[len(active):])
+	f.subscribers = active
+}
+
+// levels returns the levels of a side the subscribers have to be sent after changes, along with the prices they then
+// see. Besides the changed levels they see or saw, that's the levels which moved into the best depth because a better
+// one emptied. Levels that drop out of it aren't sent, subscribers discard what's past their depth.
+func (f *feed) levels(book *orderbook.Book, changes []orderbook.Level, shown []int64) ([]orderbook.Level, []int64) {
+	if f.depth == 0 || len(changes) == 0 {
+		return changes, shown
+	}
+	top := book.Depth(f.depth)
+	seen := make(map[int64]bool, len(shown))
+	for _, price := range shown {
+		seen[price] = true
+	}
+
+	var updates []orderbook.Level
+	for _, level := range changes {
+		if seen[level.Price] {
+			updates = append(updates, level)
+		}
+	}
+	prices := make([]int64, 0, len(top))
+	for _, level := range top {
+		if !seen[level.Price] {
+			updates = append(updates, level)
+		}
+		prices = append(prices, level.Price)
+	}
+	sort.Slice(updates, func(i, j int) bool { return updates[i].Price < updates[j].Price })
+	return updates, prices
+}
+
+func (e *Engine) SubscribeMarketData(in *pb.MarketDataRequest, stream pb.OrderService_SubscribeMarketDataServer) error {
+	sub := &subscriber{depth: max(int(in.Depth), 0), updates: make(chan *pb.MarketDataUpdate, subscriberBufferSize)}
+	defer sub.gone.Store(true)
+
+	result, err := e.submit(stream.Context(), command{kind: subscribeMarketData, symbol: in.Symbol, subscriber: sub})
+	if err != nil {
+		return err
+	}
+	if !result.success {
+		return status.Error(codes.NotFound, result.message)
+	}
+
+	for {
+		select {
+		case update, ok := <-sub.updates:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "Subscriber fell behind, resubscribe for a new snapshot")
+			}
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		case <-e.subscriptionsEnded:
+			return status.Error(codes.Unavailable, "Exchange is shutting down")
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		}
+	}
+}
+
+// EndSubscriptions terminates all streams, which would otherwise keep a graceful server shutdown waiting forever.
+func (e *Engine) EndSubscriptions() {
+	e.endSubscriptions.Do(func() { close(e.subscriptionsEnded) })
+}
+
+// processSubscribe sends the current state of the books to a new subscriber, which then gets every later update of the
+// levels within its depth.
+func processSubscribe(m *market, sub *subscriber) result {
+	bids, asks := m.buyBook.Depth(sub.depth), m.sellBook.Depth(sub.depth)
+	var f *feed
+	for _, existing := range m.feeds {
+		if existing.depth == sub.depth {
+			f = existing
+		}
+	}
+	if f == nil {
+		f = &feed{depth: sub.depth}
+		if sub.depth > 0 {
+			f.bids, f.asks = levelPrices(bids), levelPrices(asks)
+		}
+		m.feeds = append(m.feeds, f)
+	}
+
+	sub.updates <- &pb.MarketDataUpdate{
+		Seq:    f.seq,
+		Symbol: m.symbol,
+		Update: &pb.MarketDataUpdate_Snapshot{Snapshot: &pb.BookSnapshot{
+			Bids: priceLevels(bids),
+			Asks: priceLevels(asks),
+		}},
+	}
+	f.subscribers = append(f.subscribers, sub)
+	return result{success: true}
+}
+
+// publishMarketData sends the trades of a processed command, including those of the stop orders it triggered, followed
+// by the levels it changed. Feeds left without subscribers are dropped.
+func publishMarketData(m *market, result result) {
+	bids, asks := m.buyBook.TakeChanges(), m.sellBook.TakeChanges()
+	if len(m.feeds) == 0 {
+		return
+	}
+
+	active := m.feeds[:0]
+	for _, f := range m.feeds {
+		publishTrades(m, f, result)
+		for _, triggered := range result.triggered {
+			publishTrades(m, f, triggered)
+		}
+		var levels []orderbook.Level
+		levels, f.bids = f.levels(m.buyBook, bids, f.bids)
+		for _, level := range levels {
+			f.publish(m.symbol, &pb.MarketDataUpdate{Update: &pb.MarketDataUpdate_Level{Level: &pb.LevelUpdate{Side: pb.Side_SIDE_BUY, Level: priceLevel(level)}}})
+		}
+		levels, f.asks = f.levels(m.sellBook, asks, f.asks)
+		for _, level := range levels {
+			f.publish(m.symbol, &pb.MarketDataUpdate{Update: &pb.MarketDataUpdate_Level{Level: &pb.LevelUpdate{Side: pb.Side_SIDE_SELL, Level: priceLevel(level)}}})
+		}
+		if len(f.subscribers) > 0 {
+			active = append(active, f)
+		}
+	}
+	clear(m.feeds[len(active):])
+	m.feeds = active
+}
+
+func publishTrades(m *market, f *feed, executed result) {
+	for _, match := range executed.matches {
+		f.publish(m.symbol, &pb.MarketDataUpdate{Update: &pb.MarketDataUpdate_Trade{Trade: &pb.TradePrint{
+			TradeId:       match.tradeId,
+			Time:          match.time,
+			Price:         match.price,
+			Amount:        match.amount,
+			AggressorSide: sideToProto(executed.order.Type),
+		}}})
+	}
+}
+
+func priceLevel(level orderbook.Level) *pb.PriceLevel {
+	return &pb.PriceLevel{Price: level.Price, Amount: level.Amount, OrderCount: int32(level.Count)}
+}
+
+func priceLevels(levels []orderbook.Level) []*pb.PriceLevel {
+	result := make([]*pb.PriceLevel, 0, len(levels))
+	for _, level := range levels {
+		result = append(result, priceLevel(level))
+	}
+	return result
+}
+
+func levelPrices(levels []orderbook.Level) []int64 {
+	prices := make([]int64, 0, len(levels))
+	for _, level := range levels {
+		prices = append(prices, level.Price)
+	}
+	return prices
+}
@@ -0,0 +1,249 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MichalPitr/exchange/orderbook"
+	pb "github.com/MichalPitr/exchange/protos"
+)
+
+// Number of completed orders each market remembers, the oldest ones are forgotten first.
+const completedOrderRetention = 100000
+
+// orderRecord is the lifecycle of an order as told by its execution reports.
+type orderRecord struct {
+	Id        uint64              `json:"id"`
+	UserId    int32               `json:"userId"`
+	Side      orderbook.Side      `json:"side"`
+	OrderType orderbook.OrderType `json:"orderType"`
+	Price     int64               `json:"price"`
+	StopPrice int64               `json:"stopPrice,omitempty"`
+	Display   int32               `json:"display,omitempty"`
+	State     pb.OrderState       `json:"state"`
+	Amount    int32               `json:"amount"` // Amount when the order was placed.
+	Remaining int32               `json:"remaining"`
+	Filled    int32               `json:"filled"`
+	Notional  int64               `json:"notional"` // Sum of amount times price over all fills.
+	Fees      int64               `json:"fees,omitempty"`
+	Created   int64               `json:"created"`
+	Updated   int64               `json:"updated"`
+}
+
+func (r *orderRecord) open() bool {
+	return r.State == pb.OrderState_ORDER_STATE_NEW || r.State == pb.OrderState_ORDER_STATE_PARTIALLY_FILLED
+}
+
+func (r *orderRecord) info(symbol string) *pb.OrderInfo {
+	info := &pb.OrderInfo{
+		OrderId:         r.Id,
+		UserId:          r.UserId,
+		Symbol:          symbol,
+		Side:            sideToProto(r.Side),
+		OrderType:       orderTypeToProto(r.OrderType),
+		Price:           r.Price,
+		StopPrice:       r.StopPrice,
+		DisplayAmount:   r.Display,
+		State:           r.State,
+		OriginalAmount:  r.Amount,
+		RemainingAmount: r.Remaining,
+		FilledAmount:    r.Filled,
+		CreatedTime:     r.Created,
+		UpdatedTime:     r.Updated,
+		Fees:            r.Fees,
+	}
+	if r.Filled > 0 {
+		info.AveragePrice = float64(r.Notional) / float64(r.Filled)
+	}
+	return info
+}
+
+// orderRegistry tracks the orders of a market from placement until well after they are completed. It's written by the
+// market's goroutine and read by the RPC handlers.
+type orderRegistry struct {
+	mutex     sync.RWMutex
+	orders    map[uint64]*orderRecord
+	open      map[int32]map[uint64]*orderRecord // Open orders by user.
+	completed []uint64                          // Ids of completed orders, oldest first.
+}
+
+func newOrderRegistry() *orderRegistry {
+	return &orderRegistry{orders: make(map[uint64]*orderRecord), open: make(map[int32]map[uint64]*orderRecord)}
+}
+
+// record applies execution reports to the orders they are about.
+func (r *orderRegistry) record(reports []*pb.ExecutionReport) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, report := range reports {
+		order, ok := r.orders[report.OrderId]
+		if !ok {
+			order = &orderRecord{
+				Id:        report.OrderId,
+				UserId:    report.UserId,
+				Side:      sideFromProto(report.Side),
+				OrderType: orderTypeFromProto(report.OrderType),
+				StopPrice: report.StopPrice,
+				Display:   report.DisplayAmount,
+				Amount:    report.RemainingAmount,
+				Created:   report.Time,
+			}
+		}
+		order.Price = report.Price
+		order.Remaining = report.RemainingAmount
+		order.Filled = report.CumulativeAmount
+		order.Notional += int64(report.LastAmount) * report.LastPrice
+		order.Fees += report.Fee
+		order.Updated = report.Time
+		switch report.ExecType {
+		case pb.ExecType_EXEC_TYPE_NEW, pb.ExecType_EXEC_TYPE_REPLACED, pb.ExecType_EXEC_TYPE_TRIGGERED:
+			order.State = pb.OrderState_ORDER_STATE_NEW
+			if order.Filled > 0 {
+				order.State = pb.OrderState_ORDER_STATE_PARTIALLY_FILLED
+			}
+		case pb.ExecType_EXEC_TYPE_PARTIAL_FILL:
+			order.State = pb.OrderState_ORDER_STATE_PARTIALLY_FILLED
+		case pb.ExecType_EXEC_TYPE_FILL:
+			order.State = pb.OrderState_ORDER_STATE_FILLED
+		case pb.ExecType_EXEC_TYPE_CANCELLED:
+			order.State = pb.OrderState_ORDER_STATE_CANCELLED
+		case pb.ExecType_EXEC_TYPE_REJECTED:
+			order.State = pb.OrderState_ORDER_STATE_REJECTED
+		case pb.ExecType_EXEC_TYPE_EXPIRED:
+			order.State = pb.OrderState_ORDER_STATE_EXPIRED
+		}
+		r.add(order)
+	}
+}
+
+// add stores order, or updates where it's kept after its state changed.
+func (r *orderRegistry) add(order *orderRecord) {
+	r.orders[order.Id] = order
+	if order.open() {
+		if r.open[order.UserId] == nil {
+			r.open[order.UserId] = make(map[uint64]*orderRecord)
+		}
+		r.open[order.UserId][order.Id] = order
+		return
+	}
+
+	if open, ok := r.open[order.UserId]; ok {
+		delete(open, order.Id)
+		if len(open) == 0 {
+			delete(r.open, order.UserId)
+		}
+	}
+	r.completed = append(r.completed, order.Id)
+	if len(r.completed) > completedOrderRetention {
+		delete(r.orders, r.completed[0])
+		r.completed = r.completed[1:]
+	}
+}
+
+func (r *orderRegistry) get(orderId uint64) (orderRecord, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	order, ok := r.orders[orderId]
+	if !ok {
+		return orderRecord{}, false
+	}
+	return *order, true
+}
+
+func (r *orderRegistry) openOrders(userId int32) []orderRecord {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	orders := make([]orderRecord, 0, len(r.open[userId]))
+	for _, order := range r.open[userId] {
+		orders = append(orders, *order)
+	}
+	return orders
+}
+
+// records returns every order, the completed ones in the order they completed, followed by the open ones.
+func (r *orderRegistry) records() []orderRecord {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	records := make([]orderRecord, 0, len(r.orders))
+	for _, id := range r.completed {
+		records = append(records, *r.orders[id])
+	}
+	for _, open := range r.open {
+		for _, order := range open {
+			records = append(records, *order)
+		}
+	}
+	return records
+}
+
+// restore adds records as returned by records, and makes up the records of open orders that have none.
+func (r *orderRegistry) restore(records []orderRecord, pending []orderbook.Order) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i := range records {
+		r.add(&records[i])
+	}
+	for _, order := range pending {
+		if _, ok := r.orders[order.Id]; ok {
+			continue
+		}
+		state := pb.OrderState_ORDER_STATE_NEW
+		if order.Filled > 0 {
+			state = pb.OrderState_ORDER_STATE_PARTIALLY_FILLED
+		}
+		r.add(&orderRecord{
+			Id:        order.Id,
+			UserId:    order.UserID,
+			Side:      order.Type,
+			OrderType: order.OrderType,
+			Price:     order.Price,
+			StopPrice: order.StopPrice,
+			Display:   order.DisplayAmount,
+			State:     state,
+			Amount:    order.Amount + order.Hidden + order.Filled,
+			Remaining: order.Amount + order.Hidden,
+			Filled:    order.Filled,
+			Created:   order.Time,
+			Updated:   order.Time,
+		})
+	}
+}
+
+func (e *Engine) GetOrder(ctx context.Context, in *pb.GetOrderRequest) (*pb.OrderInfo, error) {
+	for _, m := range e.markets {
+		if order, ok := m.orders.get(in.OrderId); ok {
+			return order.info(m.symbol), nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "Order %d is unknown", in.OrderId)
+}
+
+func (e *Engine) ListOpenOrders(ctx context.Context, in *pb.ListOpenOrdersRequest) (*pb.ListOpenOrdersResponse, error) {
+	markets := e.markets
+	if in.Symbol != "" {
+		m, ok := e.markets[in.Symbol]
+		if !ok {
+			return nil, status.Error(codes.NotFound, fmt.Sprintf("Unknown symbol %q", in.Symbol))
+		}
+		markets = map[string]*market{in.Symbol: m}
+	}
+
+	response := &pb.ListOpenOrdersResponse{}
+	for _, m := range markets {
+		for _, order := range m.orders.openOrders(in.UserId) {
+			response.Orders = append(response.Orders, order.info(m.symbol))
+		}
+	}
+	sort.Slice(response.Orders, func(i, j int) bool { return response.Orders[i].OrderId < response.Orders[j].OrderId })
+	return response, nil
+}
@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/MichalPitr/exchange/orderbook"
+	pb "github.com/MichalPitr/exchange/protos"
+)
+
+// selfTrade tells whether two orders belong to the same trader, which is the same user or the same self-trade group.
+func selfTrade(a, b orderbook.Order) bool {
+	return a.UserID == b.UserID || (a.SelfTradeGroup != 0 && a.SelfTradeGroup == b.SelfTradeGroup)
+}
+
+func stpReason(mode orderbook.SelfTradePrevention) string {
+	return fmt.Sprintf("self-trade prevention %s", mode)
+}
+
+// preventSelfTrade applies the self-trade prevention mode of taker, which would trade with maker, the best order of
+// book. Nothing trades, cancelled orders are reported and decremented ones are reported as replaced. It returns whether
+// the remainder of taker is cancelled.
+func preventSelfTrade(m *market, book *orderbook.Book, taker *orderbook.Order, maker *orderbook.Order) bool {
+	reason := stpReason(taker.SelfTradePrevention)
+	switch taker.SelfTradePrevention {
+	case orderbook.CancelOldest:
+		cancelResting(m, book, maker.Id, reason)
+		return false
+	case orderbook.CancelBoth:
+		cancelResting(m, book, maker.Id, reason)
+		return true
+	case orderbook.DecrementAndCancel:
+		// Both orders lose the amount of the smaller one, which leaves that one with nothing.
+		decrement := min(taker.Amount, maker.Amount+maker.Hidden)
+		if decrement == maker.Amount+maker.Hidden {
+			cancelResting(m, book, maker.Id, reason)
+		} else {
+			hidden := min(decrement, maker.Hidden)
+			maker.Hidden -= hidden
+			book.Reduce(maker, decrement-hidden)
+			m.report(*maker, pb.ExecType_EXEC_TYPE_REPLACED, reason)
+		}
+		taker.Amount -= decrement
+		if taker.Amount == 0 {
+			return true
+		}
+		m.report(*taker, pb.ExecType_EXEC_TYPE_REPLACED, reason)
+		return false
+	}
+	return true // Cancel newest.
+}
@@ -0,0 +1,207 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/MichalPitr/exchange/accounts"
+	"github.com/MichalPitr/exchange/orderbook"
+	"github.com/MichalPitr/exchange/reporter"
+	"github.com/MichalPitr/exchange/snapshot"
+)
+
+// Bumped whenever marketSnapshot changes.
+const snapshotVersion = 1
+
+// marketSnapshot is the state of a market after it applied command Seq.
+type marketSnapshot struct {
+	Seq         uint64            `json:"seq"`
+	LastTime    int64             `json:"lastTime"`
+	LastTrade   int64             `json:"lastTrade"`
+	LastTradeId uint64            `json:"lastTradeId"`
+	Records     uint64            `json:"records"` // Number of the last record handed to the reporter.
+	NextOrderId uint64            `json:"nextOrderId"`
+	Buys        []orderbook.Order `json:"buys"` // In the order they trade, so adding them back restores time priority.
+	Sells       []orderbook.Order `json:"sells"`
+	Stops       []orderbook.Order `json:"stops"` // Orders waiting for their stop price.
+	Orders      []orderRecord     `json:"orders"`
+
+	Positions map[int32]accounts.Position `json:"positions,omitempty"` // What trades added to accounts.
+	Volumes   map[int32][]dayVolume       `json:"volumes,omitempty"`   // Setting fee tiers.
+	Reports   map[int32]uint64            `json:"reports,omitempty"`   // Execution reports sent per user.
+}
+
+func snapshotName(dataDir, symbol string, seq uint64) string {
+	return filepath.Join(dataDir, fmt.Sprintf("%s-%020d.snapshot", symbol, seq))
+}
+
+// takeSnapshot captures the state of m and writes it in the background, so that the matching loop only pays for
+// copying the books. Does nothing while the previous snapshot is still being written.
+func takeSnapshot(e *Engine, m *market) {
+	if m.snapshotDone != nil {
+		select {
+		case <-m.snapshotDone:
+		default:
+			return
+		}
+	}
+
+	state := marketSnapshot{
+		Seq:         m.seq,
+		LastTime:    m.lastTime,
+		LastTrade:   m.lastTrade,
+		LastTradeId: m.lastTradeId,
+		Records:     m.records,
+		NextOrderId: e.nextOrderId.Load(),
+		Buys:        m.buyBook.Orders(),
+		Sells:       m.sellBook.Orders(),
+		Stops:       append(m.buyStops.orders(), m.sellStops.orders()...),
+		Orders:      m.orders.records(),
+		Volumes:     m.fees.snapshot(m.lastTime / day),
+		Reports:     maps.Clone(m.reportCounts),
+	}
+	if m.accounts != nil {
+		state.Positions = m.accounts.Positions()
+		if err := m.accounts.Sync(); err != nil {
+			log.Fatalf("Failed to sync the ledger before snapshotting %s: %v", m.symbol, err)
+		}
+	}
+	// The records of the commands the snapshot covers have to be durable, as those commands aren't replayed anymore.
+	if durable, ok := m.reporter.(reporter.Durable); ok {
+		if err := durable.Sync(); err != nil {
+			log.Printf("Failed to sync the trade log of %s, not snapshotting: %v", m.symbol, err)
+			return
+		}
+	}
+	if err := rotateJournal(m); err != nil {
+		log.Fatalf("Failed to rotate %s journal: %v", m.symbol, err)
+	}
+	m.snapshotSeq = m.seq
+
+	done := make(chan struct{})
+	m.snapshotDone = done
+	go func(dataDir, symbol string) {
+		defer close(done)
+		if err := writeSnapshot(dataDir, symbol, state); err != nil {
+			log.Printf("Failed to snapshot %s at %d: %v", symbol, state.Seq, err)
+			return
+		}
+		if err := pruneFiles(dataDir, symbol, state.Seq); err != nil {
+			log.Printf("Failed to prune %s files: %v", symbol, err)
+		}
+	}(m.dataDir, m.symbol)
+}
+
+// waitForSnapshot blocks until the snapshot being written in the background, if any, is done.
+func waitForSnapshot(m *market) {
+	if m.snapshotDone != nil {
+		<-m.snapshotDone
+	}
+}
+
+func writeSnapshot(dataDir, symbol string, state marketSnapshot) error {
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return snapshot.Write(snapshotName(dataDir, symbol, state.Seq), snapshotVersion, payload)
+}
+
+func readSnapshot(filename string) (marketSnapshot, error) {
+	var state marketSnapshot
+	version, payload, err := snapshot.Read(filename)
+	if err != nil {
+		return state, err
+	}
+	if version != snapshotVersion {
+		return state, fmt.Errorf("unsupported snapshot version %d", version)
+	}
+	err = json.Unmarshal(payload, &state)
+	return state, err
+}
+
+// loadLatestSnapshot restores m from the newest snapshot that passes verification.
+func loadLatestSnapshot(e *Engine, m *market) error {
+	snapshots, err := listSequenced(m.dataDir, m.symbol, ".snapshot")
+	if err != nil {
+		return err
+	}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		state, err := readSnapshot(snapshots[i].filename)
+		if err != nil {
+			log.Printf("Skipping snapshot %s: %v", snapshots[i].filename, err)
+			continue
+		}
+		for _, orders := range [][]orderbook.Order{state.Buys, state.Sells} {
+			for _, order := range orders {
+				rest(m, order)
+			}
+		}
+		for _, order := range state.Stops {
+			m.stopBook(order.Type).add(order)
+		}
+		open := append(append(state.Buys, state.Sells...), state.Stops...)
+		m.orders.restore(state.Orders, open)
+		for userId, days := range state.Volumes {
+			m.fees.volumes[userId] = days
+		}
+		if m.accounts != nil {
+			m.accounts.Restore(state.Positions)
+			for _, order := range open {
+				reserve(m, order, required(m, order))
+			}
+		}
+		m.seq = state.Seq
+		m.snapshotSeq = state.Seq
+		m.lastTime = state.LastTime
+		m.lastTrade = state.LastTrade
+		m.lastTradeId = state.LastTradeId
+		m.records = state.Records
+		for userId, count := range state.Reports {
+			m.reportCounts[userId] = count
+		}
+		raiseNextOrderId(e, state.NextOrderId)
+		return nil
+	}
+	return nil
+}
+
+// pruneFiles deletes snapshots and journal segments that are no longer needed after the snapshot at seq was written.
+// The snapshot before it is kept, together with the journal after it, in case the newest one turns out to be corrupted.
+func pruneFiles(dataDir, symbol string, seq uint64) error {
+	snapshots, err := listSequenced(dataDir, symbol, ".snapshot")
+	if err != nil {
+		return err
+	}
+	keepFrom := seq
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if snapshots[i].seq < seq {
+			keepFrom = snapshots[i].seq
+			break
+		}
+	}
+	for _, s := range snapshots {
+		if s.seq < keepFrom {
+			if err := os.Remove(s.filename); err != nil {
+				return err
+			}
+		}
+	}
+
+	segments, err := listSequenced(dataDir, symbol, ".journal")
+	if err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i+1].seq-1 <= keepFrom {
+			if err := os.Remove(segments[i].filename); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package engine
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/MichalPitr/exchange/orderbook"
+	pb "github.com/MichalPitr/exchange/protos"
+)
+
+type stopItem struct {
+	order orderbook.Order
+	index int
+}
+
+// stopBook holds the STOP and STOP_LIMIT orders of one side until a trade reaches their stop price. Orders are kept in
+// the order they trigger: buy stops trigger as the price rises, so the lowest stop price comes first, sell stops the
+// other way around. Orders with the same stop price trigger in arrival order.
+type stopBook struct {
+	items []*stopItem
+	byId  map[uint64]*stopItem
+	side  orderbook.Side
+}
+
+func newStopBook(side orderbook.Side) *stopBook {
+	return &stopBook{byId: make(map[uint64]*stopItem), side: side}
+}
+
+func (b stopBook) Len() int { return len(b.items) }
+
+func (b stopBook) Less(i, j int) bool {
+	x, y := b.items[i].order, b.items[j].order
+	if x.StopPrice == y.StopPrice {
+		return x.Time < y.Time
+	}
+	if b.side == orderbook.Buy {
+		return x.StopPrice < y.StopPrice
+	}
+	return x.StopPrice > y.StopPrice
+}
+
+func (b stopBook) Swap(i, j int) {
+	b.items[i], b.items[j] = b.items[j], b.items[i]
+	b.items[i].index = i
+	b.items[j].index = j
+}
+
+func (b *stopBook) Push(x any) {
+	item := x.(*stopItem)
+	item.index = len(b.items)
+	b.items = append(b.items, item)
+	b.byId[item.order.Id] = item
+}
+
+func (b *stopBook) Pop() any {
+	n := len(b.items)
+	item := b.items[n-1]
+	b.items[n-1] = nil
+	b.items = b.items[:n-1]
+	delete(b.byId, item.order.Id)
+	return item
+}
+
+func (b *stopBook) add(order orderbook.Order) {
+	heap.Push(b, &stopItem{order: order})
+}
+
+func (b *stopBook) get(id uint64) (orderbook.Order, bool) {
+	item, ok := b.byId[id]
+	if !ok {
+		return orderbook.Order{}, false
+	}
+	return item.order, true
+}
+
+func (b *stopBook) remove(id uint64) (orderbook.Order, bool) {
+	item, ok := b.byId[id]
+	if !ok {
+		return orderbook.Order{}, false
+	}
+	heap.Remove(b, item.index)
+	return item.order, true
+}
+
+// triggered removes and returns the first order whose stop price the last trade reached.
+func (b *stopBook) triggered(lastTrade int64) (orderbook.Order, bool) {
+	if len(b.items) == 0 {
+		return orderbook.Order{}, false
+	}
+	top := b.items[0].order
+	if !reached(top, lastTrade) {
+		return orderbook.Order{}, false
+	}
+	heap.Pop(b)
+	return top, true
+}
+
+func (b *stopBook) orders() []orderbook.Order {
+	orders := make([]orderbook.Order, 0, len(b.items))
+	for _, item := range b.items {
+		orders = append(orders, item.order)
+	}
+	return orders
+}
+
+func (m *market) stopBook(side orderbook.Side) *stopBook {
+	if side == orderbook.Buy {
+		return m.buyStops
+	}
+	return m.sellStops
+}
+
+// releaseStops executes the stop orders triggered by the trades of the command being applied, including those triggered
+// by the trades of released orders. Buy stops are released before sell stops, each in trigger order.
+func releaseStops(m *market) []result {
+	var released []result
+	for {
+		order, ok := m.buyStops.triggered(m.lastTrade)
+		if !ok {
+			order, ok = m.sellStops.triggered(m.lastTrade)
+		}
+		if !ok {
+			return released
+		}
+
+		released = append(released, trigger(m, order))
+	}
+}
+
+// trigger turns a stop order into the order it stands for, and executes it.
+func trigger(m *market, order orderbook.Order) result {
+	order.Time = m.commandTime
+	if order.OrderType == orderbook.Stop {
+		order.OrderType = orderbook.Market
+	} else {
+		order.OrderType = orderbook.Limit
+	}
+	m.report(order, pb.ExecType_EXEC_TYPE_TRIGGERED, "")
+	return executeOrder(m, order)
+}
+
+// reached tells whether a trade at price releases a stop order.
+func reached(order orderbook.Order, price int64) bool {
+	if price == 0 {
+		return false
+	}
+	if order.Type == orderbook.Buy {
+		return price >= order.StopPrice
+	}
+	return price <= order.StopPrice
+}
+
+// cancelStop cancels an order that waits for its stop price.
+func cancelStop(m *market, orderId uint64, userId int32) (result, bool) {
+	for _, book := range []*stopBook{m.buyStops, m.sellStops} {
+		if order, ok := book.get(orderId); ok && order.UserID == userId {
+			book.remove(orderId)
+			m.report(order, pb.ExecType_EXEC_TYPE_CANCELLED, "Cancelled by user")
+			return result{success: true, message: fmt.Sprintf("Order %d cancelled", orderId), order: order}, true
+		}
+	}
+	return result{}, false
+}
@@ -0,0 +1,229 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MichalPitr/exchange/orderbook"
+	pb "github.com/MichalPitr/exchange/protos"
+)
+
+// Domain of the google.rpc.ErrorInfo attached to rejected requests.
+const errorDomain = "exchange"
+
+// invalidArgument builds an INVALID_ARGUMENT status that tells clients which field is wrong and why.
+func invalidArgument(reason pb.RejectReason, field string, format string, args ...any) error {
+	return withReason(codes.InvalidArgument, reason, field, format, args...)
+}
+
+// failedPrecondition builds a FAILED_PRECONDITION status for requests that are valid, but can't be done in the state
+// the exchange is in.
+func failedPrecondition(reason pb.RejectReason, field string, format string, args ...any) error {
+	return withReason(codes.FailedPrecondition, reason, field, format, args...)
+}
+
+func withReason(code codes.Code, reason pb.RejectReason, field string, format string, args ...any) error {
+	st := status.New(code, fmt.Sprintf(format, args...))
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason.String(),
+		Domain:   errorDomain,
+		Metadata: map[string]string{"field": field},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// validateOrder checks a request before it's assigned an id, so that malformed orders never reach a market.
+func validateOrder(in *pb.OrderRequest, now time.Time) error {
+	if in.Symbol == "" {
+		return invalidArgument(pb.RejectReason_REJECT_REASON_MISSING_SYMBOL, "symbol", "Symbol is required")
+	}
+	if in.Side != pb.Side_SIDE_BUY && in.Side != pb.Side_SIDE_SELL {
+		return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_SIDE, "side", "Side %v is not BUY or SELL", in.Side)
+	}
+	if in.Amount <= 0 {
+		return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_AMOUNT, "amount", "Amount %d is not positive", in.Amount)
+	}
+
+	switch in.OrderType {
+	case pb.OrderType_ORDER_TYPE_LIMIT:
+		if in.Price <= 0 {
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_PRICE, "price", "Limit price %d is not positive", in.Price)
+		}
+		if _, ok := pb.TimeInForce_name[int32(in.TimeInForce)]; !ok {
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_TIME_IN_FORCE, "timeInForce", "Time in force %v is unknown", in.TimeInForce)
+		}
+		if in.MaxSlippageTicks != 0 {
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_SLIPPAGE, "maxSlippageTicks", "Slippage protection only applies to MARKET orders")
+		}
+	case pb.OrderType_ORDER_TYPE_MARKET, pb.OrderType_ORDER_TYPE_STOP:
+		if in.MaxSlippageTicks < 0 {
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_SLIPPAGE, "maxSlippageTicks", "Slippage %d is negative", in.MaxSlippageTicks)
+		}
+		switch in.TimeInForce {
+		case pb.TimeInForce_TIME_IN_FORCE_UNSPECIFIED, pb.TimeInForce_TIME_IN_FORCE_IOC, pb.TimeInForce_TIME_IN_FORCE_FOK:
+		default:
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_TIME_IN_FORCE, "timeInForce", "Time in force %v is not supported for %v orders", in.TimeInForce, in.OrderType)
+		}
+	case pb.OrderType_ORDER_TYPE_STOP_LIMIT:
+		if in.Price <= 0 {
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_PRICE, "price", "Limit price %d is not positive", in.Price)
+		}
+		if in.MaxSlippageTicks != 0 {
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_SLIPPAGE, "maxSlippageTicks", "Slippage protection only applies to MARKET orders")
+		}
+		// Only stop orders that are in the orderbook once triggered expire.
+		switch in.TimeInForce {
+		case pb.TimeInForce_TIME_IN_FORCE_DAY, pb.TimeInForce_TIME_IN_FORCE_GTD:
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_TIME_IN_FORCE, "timeInForce", "Time in force %v is not supported for STOP_LIMIT orders", in.TimeInForce)
+		}
+		if _, ok := pb.TimeInForce_name[int32(in.TimeInForce)]; !ok {
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_TIME_IN_FORCE, "timeInForce", "Time in force %v is unknown", in.TimeInForce)
+		}
+	default:
+		return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_ORDER_TYPE, "orderType", "Order type %v is unknown", in.OrderType)
+	}
+
+	if in.DisplayAmount != 0 {
+		if in.OrderType != pb.OrderType_ORDER_TYPE_LIMIT {
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_DISPLAY_AMOUNT, "displayAmount", "Only LIMIT orders can be icebergs")
+		}
+		if in.DisplayAmount < 0 || in.DisplayAmount >= in.Amount {
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_DISPLAY_AMOUNT, "displayAmount", "Display amount %d is not between 0 and amount %d", in.DisplayAmount, in.Amount)
+		}
+		switch in.TimeInForce {
+		case pb.TimeInForce_TIME_IN_FORCE_IOC, pb.TimeInForce_TIME_IN_FORCE_FOK:
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_DISPLAY_AMOUNT, "displayAmount", "Orders that never rest can't be icebergs")
+		}
+	}
+
+	if in.PostOnly != pb.PostOnly_POST_ONLY_UNSPECIFIED {
+		if _, ok := pb.PostOnly_name[int32(in.PostOnly)]; !ok {
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_POST_ONLY, "postOnly", "Post-only %v is unknown", in.PostOnly)
+		}
+		if in.OrderType != pb.OrderType_ORDER_TYPE_LIMIT {
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_POST_ONLY, "postOnly", "Only LIMIT orders can be post-only")
+		}
+		switch in.TimeInForce {
+		case pb.TimeInForce_TIME_IN_FORCE_IOC, pb.TimeInForce_TIME_IN_FORCE_FOK:
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_POST_ONLY, "postOnly", "Orders that never rest can't be post-only")
+		}
+	}
+
+	if _, ok := pb.SelfTradePrevention_name[int32(in.SelfTradePrevention)]; !ok {
+		return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_SELF_TRADE_PREVENTION, "selfTradePrevention", "Self-trade prevention %v is unknown", in.SelfTradePrevention)
+	}
+	if in.SelfTradeGroup < 0 {
+		return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_SELF_TRADE_PREVENTION, "selfTradeGroup", "Self-trade group %d is negative", in.SelfTradeGroup)
+	}
+
+	stop := in.OrderType == pb.OrderType_ORDER_TYPE_STOP || in.OrderType == pb.OrderType_ORDER_TYPE_STOP_LIMIT
+	if stop && in.StopPrice <= 0 {
+		return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_STOP_PRICE, "stopPrice", "Stop price %d is not positive", in.StopPrice)
+	}
+	if !stop && in.StopPrice != 0 {
+		return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_STOP_PRICE, "stopPrice", "Stop price only applies to STOP and STOP_LIMIT orders")
+	}
+
+	if in.TimeInForce == pb.TimeInForce_TIME_IN_FORCE_GTD {
+		if in.ExpireTime <= now.UnixNano() {
+			return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_EXPIRE_TIME, "expireTime", "Expire time %d is not in the future", in.ExpireTime)
+		}
+	} else if in.ExpireTime != 0 {
+		return invalidArgument(pb.RejectReason_REJECT_REASON_INVALID_EXPIRE_TIME, "expireTime", "Expire time only applies to GTD orders")
+	}
+	return nil
+}
+
+func sideFromProto(side pb.Side) orderbook.Side {
+	if side == pb.Side_SIDE_SELL {
+		return orderbook.Sell
+	}
+	return orderbook.Buy
+}
+
+// timeInForceFromProto returns the time in force of an order, filling in the default of its type.
+func timeInForceFromProto(timeInForce pb.TimeInForce, orderType orderbook.OrderType) orderbook.TimeInForce {
+	switch timeInForce {
+	case pb.TimeInForce_TIME_IN_FORCE_GTC:
+		return orderbook.GTC
+	case pb.TimeInForce_TIME_IN_FORCE_IOC:
+		return orderbook.IOC
+	case pb.TimeInForce_TIME_IN_FORCE_FOK:
+		return orderbook.FOK
+	case pb.TimeInForce_TIME_IN_FORCE_DAY:
+		return orderbook.Day
+	case pb.TimeInForce_TIME_IN_FORCE_GTD:
+		return orderbook.GTD
+	}
+	if orderType == orderbook.Market || orderType == orderbook.Stop {
+		return orderbook.IOC
+	}
+	return orderbook.GTC
+}
+
+func postOnlyFromProto(postOnly pb.PostOnly) orderbook.PostOnly {
+	switch postOnly {
+	case pb.PostOnly_POST_ONLY_REJECT:
+		return orderbook.PostOnlyReject
+	case pb.PostOnly_POST_ONLY_REPRICE:
+		return orderbook.PostOnlyReprice
+	}
+	return ""
+}
+
+func selfTradePreventionFromProto(mode pb.SelfTradePrevention) orderbook.SelfTradePrevention {
+	switch mode {
+	case pb.SelfTradePrevention_SELF_TRADE_PREVENTION_CANCEL_NEWEST:
+		return orderbook.CancelNewest
+	case pb.SelfTradePrevention_SELF_TRADE_PREVENTION_CANCEL_OLDEST:
+		return orderbook.CancelOldest
+	case pb.SelfTradePrevention_SELF_TRADE_PREVENTION_CANCEL_BOTH:
+		return orderbook.CancelBoth
+	case pb.SelfTradePrevention_SELF_TRADE_PREVENTION_DECREMENT_AND_CANCEL:
+		return orderbook.DecrementAndCancel
+	}
+	return ""
+}
+
+func sideToProto(side orderbook.Side) pb.Side {
+	switch side {
+	case orderbook.Buy:
+		return pb.Side_SIDE_BUY
+	case orderbook.Sell:
+		return pb.Side_SIDE_SELL
+	}
+	return pb.Side_SIDE_UNSPECIFIED
+}
+
+func orderTypeFromProto(orderType pb.OrderType) orderbook.OrderType {
+	switch orderType {
+	case pb.OrderType_ORDER_TYPE_MARKET:
+		return orderbook.Market
+	case pb.OrderType_ORDER_TYPE_STOP:
+		return orderbook.Stop
+	case pb.OrderType_ORDER_TYPE_STOP_LIMIT:
+		return orderbook.StopLimit
+	}
+	return orderbook.Limit
+}
+
+func orderTypeToProto(orderType orderbook.OrderType) pb.OrderType {
+	switch orderType {
+	case orderbook.Limit:
+		return pb.OrderType_ORDER_TYPE_LIMIT
+	case orderbook.Market:
+		return pb.OrderType_ORDER_TYPE_MARKET
+	case orderbook.Stop:
+		return pb.OrderType_ORDER_TYPE_STOP
+	case orderbook.StopLimit:
+		return pb.OrderType_ORDER_TYPE_STOP_LIMIT
+	}
+	return pb.OrderType_ORDER_TYPE_UNSPECIFIED
+}
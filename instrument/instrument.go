@@ -0,0 +1,109 @@
+package instrument
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Instrument is a symbol that can be traded on the exchange.
+type Instrument struct {
+	Symbol   string `json:"symbol"`
+	TickSize int64  `json:"tickSize"` // Smallest price increment, defaults to 1.
+	// MARKET orders don't trade further than this many ticks away from the last trade, 0 disables the collar.
+	MarketCollarTicks int64 `json:"marketCollarTicks"`
+	// Fee tiers by volume, starting at 0. Trading is free if there are none.
+	Fees []FeeTier `json:"fees"`
+}
+
+// Basis points are hundredths of a percent.
+const BasisPoints = 10000
+
+// FeeTier is what a user pays once the notional they traded in the instrument over the last 30 days reaches MinVolume.
+// Rates are in basis points of the notional of each fill. Negative maker rates are rebates, which can't be more than
+// the lowest taker rate, so that the exchange never pays out more than it charges for a trade.
+type FeeTier struct {
+	MinVolume int64 `json:"minVolume"`
+	MakerBps  int64 `json:"makerBps"`
+	TakerBps  int64 `json:"takerBps"`
+}
+
+func validateFees(tiers []FeeTier) error {
+	if len(tiers) == 0 {
+		return nil
+	}
+	if tiers[0].MinVolume != 0 {
+		return fmt.Errorf("first fee tier starts at volume %d instead of 0", tiers[0].MinVolume)
+	}
+	lowestTaker := int64(BasisPoints)
+	for i, tier := range tiers {
+		if i > 0 && tier.MinVolume <= tiers[i-1].MinVolume {
+			return fmt.Errorf("fee tiers are not sorted by increasing volume")
+		}
+		if tier.TakerBps < 0 || tier.TakerBps >= BasisPoints || tier.MakerBps <= -BasisPoints || tier.MakerBps >= BasisPoints {
+			return fmt.Errorf("fee tier at volume %d has rates out of range", tier.MinVolume)
+		}
+		lowestTaker = min(lowestTaker, tier.TakerBps)
+	}
+	for _, tier := range tiers {
+		if -tier.MakerBps > lowestTaker {
+			return fmt.Errorf("maker rebate of %d bps at volume %d is more than the lowest taker fee of %d bps", -tier.MakerBps, tier.MinVolume, lowestTaker)
+		}
+	}
+	return nil
+}
+
+// Registry holds the instruments listed on the exchange, in listing order.
+type Registry struct {
+	instruments []Instrument
+	bySymbol    map[string]Instrument
+}
+
+func NewRegistry(instruments ...Instrument) (*Registry, error) {
+	r := &Registry{bySymbol: make(map[string]Instrument, len(instruments))}
+	for _, inst := range instruments {
+		if inst.Symbol == "" {
+			return nil, fmt.Errorf("instrument without symbol")
+		}
+		if _, ok := r.bySymbol[inst.Symbol]; ok {
+			return nil, fmt.Errorf("duplicate instrument %s", inst.Symbol)
+		}
+		if inst.TickSize < 0 || inst.MarketCollarTicks < 0 {
+			return nil, fmt.Errorf("instrument %s has a negative tick size or collar", inst.Symbol)
+		}
+		if err := validateFees(inst.Fees); err != nil {
+			return nil, fmt.Errorf("instrument %s: %w", inst.Symbol, err)
+		}
+		if inst.TickSize == 0 {
+			inst.TickSize = 1
+		}
+		r.instruments = append(r.instruments, inst)
+		r.bySymbol[inst.Symbol] = inst
+	}
+	return r, nil
+}
+
+// Load reads a JSON array of instruments from filename.
+func Load(filename string) (*Registry, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var instruments []Instrument
+	if err := json.Unmarshal(data, &instruments); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	if len(instruments) == 0 {
+		return nil, fmt.Errorf("no instruments in %s", filename)
+	}
+	return NewRegistry(instruments...)
+}
+
+func (r *Registry) Get(symbol string) (Instrument, bool) {
+	inst, ok := r.bySymbol[symbol]
+	return inst, ok
+}
+
+func (r *Registry) Instruments() []Instrument {
+	return r.instruments
+}
@@ -0,0 +1,55 @@
+package instrument
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "instruments.json")
+	if err := os.WriteFile(filename, []byte(`[{"symbol": "AAPL"}, {"symbol": "MSFT"}]`), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err := Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(registry.Instruments()) != 2 {
+		t.Errorf("Expected 2 instruments, but got %d", len(registry.Instruments()))
+	}
+	if inst, ok := registry.Get("MSFT"); !ok || inst.TickSize != 1 {
+		t.Errorf("Expected MSFT to be listed with the default tick size, but got %v", inst)
+	}
+	if _, ok := registry.Get("GOOG"); ok {
+		t.Error("Expected GOOG to not be listed.")
+	}
+}
+
+func TestDuplicateSymbol(t *testing.T) {
+	if _, err := NewRegistry(Instrument{Symbol: "AAPL"}, Instrument{Symbol: "AAPL"}); err == nil {
+		t.Error("Expected duplicate symbol to be rejected.")
+	}
+}
+
+func TestFeeTiers(t *testing.T) {
+	tests := []struct {
+		name  string
+		tiers []FeeTier
+		valid bool
+	}{
+		{"no fees", nil, true},
+		{"maker rebate", []FeeTier{{0, 1, 10}, {1000000, -2, 5}}, true},
+		{"first tier above zero", []FeeTier{{100, 1, 10}}, false},
+		{"unsorted", []FeeTier{{0, 1, 10}, {1000, 1, 8}, {1000, 1, 6}}, false},
+		{"negative taker fee", []FeeTier{{0, 1, -1}}, false},
+		{"rebate above taker fee", []FeeTier{{0, 1, 10}, {1000, -6, 5}}, false},
+	}
+	for _, test := range tests {
+		_, err := NewRegistry(Instrument{Symbol: "AAPL", Fees: test.tiers})
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid to be %v, but got %v", test.name, test.valid, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// Each record is framed by its length and CRC32 checksum, so that a torn write at the end of the file can be detected.
+const headerSize = 8
+
+// Records larger than this are treated as corruption rather than allocated.
+const maxRecordSize = 1 << 20
+
+// Journal is an append-only file of records. Appends are buffered until Sync, which makes them durable.
+type Journal struct {
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// Open opens the journal for appending, creating it if needed. A torn record at the end of the file is trimmed.
+func Open(filename string) (*Journal, error) {
+	valid, err := Replay(filename, func([]byte) error { return nil })
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Truncate(valid); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(valid, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &Journal{file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+func (j *Journal) Append(record []byte) error {
+	var header [headerSize]byte
+	binary.LittleEndian.PutUint32(header[0:4], uint32(len(record)))
+	binary.LittleEndian.PutUint32(header[4:8], crc32.ChecksumIEEE(record))
+	if _, err := j.writer.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := j.writer.Write(record)
+	return err
+}
+
+// Sync flushes buffered records and fsyncs the file.
+func (j *Journal) Sync() error {
+	if err := j.writer.Flush(); err != nil {
+		return err
+	}
+	return j.file.Sync()
+}
+
+func (j *Journal) Close() error {
+	if err := j.Sync(); err != nil {
+		return err
+	}
+	return j.file.Close()
+}
+
+// Replay calls fn for every intact record in the journal, in append order. It stops at the first torn or corrupted
+// record and returns the offset up to which the file is valid.
+func Replay(filename string, fn func(record []byte) error) (int64, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var offset int64
+	var header [headerSize]byte
+	for {
+		if _, err := io.ReadFull(reader, header[:]); err != nil {
+			return offset, nil
+		}
+		size := binary.LittleEndian.Uint32(header[0:4])
+		if size > maxRecordSize {
+			return offset, nil
+		}
+		record := make([]byte, size)
+		if _, err := io.ReadFull(reader, record); err != nil {
+			return offset, nil
+		}
+		if crc32.ChecksumIEEE(record) != binary.LittleEndian.Uint32(header[4:8]) {
+			return offset, nil
+		}
+		if err := fn(record); err != nil {
+			return offset, fmt.Errorf("replaying record at offset %d: %w", offset, err)
+		}
+		offset += headerSize + int64(size)
+	}
+}
@@ -0,0 +1,74 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplay(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.journal")
+	j, err := Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range []string{"first", "second", "third"} {
+		if err := j.Append([]byte(record)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var records []string
+	if _, err := Replay(filename, func(record []byte) error {
+		records = append(records, string(record))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0] != "first" || records[2] != "third" {
+		t.Errorf("Expected records in append order, but got %v", records)
+	}
+}
+
+func TestTornRecordIsTrimmed(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.journal")
+	j, err := Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Append([]byte("complete"))
+	j.Append([]byte("torn"))
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash in the middle of writing the last record.
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(filename, info.Size()-2); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err = Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Append([]byte("after restart"))
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var records []string
+	Replay(filename, func(record []byte) error {
+		records = append(records, string(record))
+		return nil
+	})
+	if len(records) != 2 || records[0] != "complete" || records[1] != "after restart" {
+		t.Errorf("Expected torn record to be dropped, but got %v", records)
+	}
+}
@@ -0,0 +1,260 @@
+// Package ledger records every change to the balances of accounts as balanced double-entry entries, so that balances
+// can be audited rather than trusted.
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MichalPitr/exchange/journal"
+)
+
+// External is the account that deposits come from and withdrawals go to. Its balance of an asset is minus the supply of
+// the asset on the exchange.
+const External = "external"
+
+// Fees is the account that trading fees are paid into, and maker rebates paid out of.
+const Fees = "fees"
+
+// Prefix of the accounts of users.
+const userPrefix = "user:"
+
+// UserAccount returns the account of a user.
+func UserAccount(userId int32) string {
+	return userPrefix + strconv.Itoa(int(userId))
+}
+
+// Posting moves Amount of Asset into Account, or out of it if Amount is negative.
+type Posting struct {
+	Account string `json:"account"`
+	Asset   string `json:"asset"`
+	Amount  int64  `json:"amount"`
+}
+
+// Entry is one balance change, made of postings that add up to zero for every asset.
+type Entry struct {
+	Id       string    `json:"id"` // trade:SYMBOL:TRADEID or transfer:TRANSFERID.
+	Time     int64     `json:"time"`
+	Symbol   string    `json:"symbol,omitempty"`
+	TradeId  uint64    `json:"tradeId,omitempty"`  // Set for the settlement of a trade.
+	Transfer uint64    `json:"transfer,omitempty"` // Set for deposits and withdrawals, numbered from 1.
+	Postings []Posting `json:"postings"`
+}
+
+// Balanced returns an error if the postings of e don't add up to zero for some asset.
+func (e Entry) Balanced() error {
+	sums := make(map[string]int64)
+	for _, p := range e.Postings {
+		sums[p.Asset] += p.Amount
+	}
+	for asset, sum := range sums {
+		if sum != 0 {
+			return fmt.Errorf("entry %s is off by %d %s", e.Id, sum, asset)
+		}
+	}
+	return nil
+}
+
+// Ledger is an append-only file of entries. Entries are buffered until Sync. It's not safe for concurrent use.
+type Ledger struct {
+	journal      *journal.Journal
+	lastTrades   map[string]uint64 // Id of the last trade posted for every symbol.
+	lastTransfer uint64
+	unsynced     bool // Whether entries were posted since the last Sync.
+}
+
+// Open opens the ledger in filename for appending, creating it if needed. A torn entry at its end is trimmed.
+func Open(filename string) (*Ledger, error) {
+	l := &Ledger{lastTrades: make(map[string]uint64)}
+	err := Read(filename, func(e Entry) error {
+		if e.TradeId != 0 {
+			l.lastTrades[e.Symbol] = max(l.lastTrades[e.Symbol], e.TradeId)
+		}
+		l.lastTransfer = max(l.lastTransfer, e.Transfer)
+		return nil
+	})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if l.journal, err = journal.Open(filename); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Read calls fn for every intact entry of the ledger in filename, in the order they were posted.
+func Read(filename string, fn func(Entry) error) error {
+	_, err := journal.Replay(filename, func(record []byte) error {
+		var e Entry
+		if err := json.Unmarshal(record, &e); err != nil {
+			return err
+		}
+		return fn(e)
+	})
+	return err
+}
+
+// PostTrade posts the settlement of a trade, unless it was posted before. Markets settle the trades they replay after
+// a restart again, which posts those that didn't make it into the ledger.
+func (l *Ledger) PostTrade(symbol string, tradeId uint64, time int64, postings []Posting) error {
+	if tradeId <= l.lastTrades[symbol] {
+		return nil
+	}
+	l.lastTrades[symbol] = tradeId
+	return l.post(Entry{Id: fmt.Sprintf("trade:%s:%d", symbol, tradeId), Time: time, Symbol: symbol, TradeId: tradeId, Postings: postings})
+}
+
+// PostTransfer posts a deposit or withdrawal, unless it was posted before.
+func (l *Ledger) PostTransfer(transfer uint64, time int64, postings []Posting) error {
+	if transfer <= l.lastTransfer {
+		return nil
+	}
+	l.lastTransfer = transfer
+	return l.post(Entry{Id: fmt.Sprintf("transfer:%d", transfer), Time: time, Transfer: transfer, Postings: postings})
+}
+
+func (l *Ledger) post(e Entry) error {
+	if err := e.Balanced(); err != nil {
+		return err
+	}
+	record, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	l.unsynced = true
+	return l.journal.Append(record)
+}
+
+// Sync makes the posted entries durable.
+func (l *Ledger) Sync() error {
+	if !l.unsynced {
+		return nil
+	}
+	l.unsynced = false
+	return l.journal.Sync()
+}
+
+func (l *Ledger) Close() error {
+	return l.journal.Close()
+}
+
+// Report is the outcome of reconciling a ledger.
+type Report struct {
+	Entries  int
+	Balances map[string]map[string]int64 // Balance of every account by asset, including External.
+	Supply   map[string]int64            // Amount of every asset deposited and not withdrawn.
+	Problems []string
+}
+
+// Reconcile replays the ledger in filename and checks that it conserves balances: every entry is balanced and posted
+// once, trades of a symbol are posted in order without gaps, no user account is ever overdrawn, and the accounts
+// other than External hold exactly the supply of every asset.
+func Reconcile(filename string) (Report, error) {
+	report := Report{Balances: make(map[string]map[string]int64), Supply: make(map[string]int64)}
+	problem := func(format string, args ...any) {
+		report.Problems = append(report.Problems, fmt.Sprintf(format, args...))
+	}
+	seen := make(map[string]bool)
+	lastTrades := make(map[string]uint64)
+	err := Read(filename, func(e Entry) error {
+		report.Entries++
+		if seen[e.Id] {
+			problem("entry %s is posted twice", e.Id)
+		}
+		seen[e.Id] = true
+		if err := e.Balanced(); err != nil {
+			problem("%v", err)
+		}
+		if e.TradeId != 0 {
+			if last := lastTrades[e.Symbol]; last != 0 && e.TradeId != last+1 {
+				problem("trades %d to %d of %s are missing before entry %s", last+1, e.TradeId-1, e.Symbol, e.Id)
+			}
+			lastTrades[e.Symbol] = e.TradeId
+		}
+		for _, p := range e.Postings {
+			balances, ok := report.Balances[p.Account]
+			if !ok {
+				balances = make(map[string]int64)
+				report.Balances[p.Account] = balances
+			}
+			balances[p.Asset] += p.Amount
+			if p.Account == External {
+				report.Supply[p.Asset] -= p.Amount
+			}
+		}
+		// Overdrafts are checked once the whole entry is posted, a user may be on both sides of a trade.
+		for _, p := range e.Postings {
+			if balance := report.Balances[p.Account][p.Asset]; p.Account != External && p.Amount < 0 && balance < 0 {
+				problem("entry %s overdraws %s by %d %s", e.Id, p.Account, -balance, p.Asset)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	held := make(map[string]int64)
+	for account, balances := range report.Balances {
+		if account == External {
+			continue
+		}
+		for asset, amount := range balances {
+			held[asset] += amount
+		}
+	}
+	assets := make([]string, 0, len(held))
+	for asset := range held {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	for _, asset := range assets {
+		if held[asset] != report.Supply[asset] {
+			problem("accounts hold %d %s, but the supply is %d", held[asset], asset, report.Supply[asset])
+		}
+	}
+	return report, nil
+}
+
+// Compare adds a problem to r for every asset of a user account that has a different balance in the ledger than in
+// actual, which holds the balances of user accounts by asset as the exchange keeps them. Missing balances are 0.
+func (r *Report) Compare(actual map[string]map[string]int64) {
+	accounts := make(map[string]bool)
+	for account := range r.Balances {
+		accounts[account] = strings.HasPrefix(account, userPrefix)
+	}
+	for account := range actual {
+		accounts[account] = true
+	}
+	for _, account := range sortedKeys(accounts) {
+		if !accounts[account] {
+			continue
+		}
+		assets := make(map[string]bool)
+		for asset := range r.Balances[account] {
+			assets[asset] = true
+		}
+		for asset := range actual[account] {
+			assets[asset] = true
+		}
+		for _, asset := range sortedKeys(assets) {
+			if posted, held := r.Balances[account][asset], actual[account][asset]; posted != held {
+				r.Problems = append(r.Problems, fmt.Sprintf("%s has %d %s in the ledger, but %d in its account", account, posted, asset, held))
+			}
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
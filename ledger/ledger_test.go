@@ -0,0 +1,111 @@
+package ledger
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func deposit(userId int32, asset string, amount int64) []Posting {
+	return []Posting{{UserAccount(userId), asset, amount}, {External, asset, -amount}}
+}
+
+func trade(buyerId, sellerId int32, amount, price int64) []Posting {
+	return []Posting{
+		{UserAccount(buyerId), "CASH", -amount * price},
+		{UserAccount(sellerId), "CASH", amount * price},
+		{UserAccount(sellerId), "AAPL", -amount},
+		{UserAccount(buyerId), "AAPL", amount},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "ledger")
+	l, err := Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.PostTransfer(1, 10, deposit(1, "CASH", 1000))
+	l.PostTransfer(2, 11, deposit(2, "AAPL", 10))
+	l.PostTrade("AAPL", 1, 12, trade(1, 2, 4, 100))
+	l.Close()
+
+	// Reopening skips what was posted before, like trades replayed after a restart.
+	if l, err = Open(filename); err != nil {
+		t.Fatal(err)
+	}
+	l.PostTrade("AAPL", 1, 12, trade(1, 2, 4, 100))
+	l.PostTrade("AAPL", 2, 13, trade(1, 2, 1, 100))
+	if err := l.PostTrade("AAPL", 3, 14, []Posting{{UserAccount(1), "CASH", -5}}); err == nil {
+		t.Error("Expected an unbalanced entry to be refused")
+	}
+	l.Close()
+
+	report, err := Reconcile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Entries != 4 || len(report.Problems) != 0 {
+		t.Fatalf("Expected 4 entries without problems, but got %+v", report)
+	}
+	if cash := report.Balances[UserAccount(1)]["CASH"]; cash != 500 {
+		t.Errorf("Expected user 1 to have 500 cash left, but got %d", cash)
+	}
+	if report.Supply["CASH"] != 1000 || report.Supply["AAPL"] != 10 {
+		t.Errorf("Expected the deposits to be the supply, but got %v", report.Supply)
+	}
+}
+
+func TestReconcileFindsProblems(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "ledger")
+	l, err := Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.PostTransfer(1, 10, deposit(1, "CASH", 100))
+	l.PostTransfer(2, 11, deposit(2, "AAPL", 10))
+	l.PostTrade("AAPL", 1, 12, trade(1, 2, 4, 100))
+	l.PostTrade("AAPL", 3, 13, trade(2, 1, 1, 100))
+	l.Close()
+
+	report, err := Reconcile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems := strings.Join(report.Problems, "\n")
+	if !strings.Contains(problems, "overdraws user:1 by 300 CASH") || !strings.Contains(problems, "trades 2 to 2 of AAPL are missing") {
+		t.Errorf("Expected the overdraft and the missing trade, but got %q", problems)
+	}
+}
+
+func TestCompareWithAccounts(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "ledger")
+	l, err := Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.PostTransfer(1, 10, deposit(1, "CASH", 1000))
+	l.PostTransfer(2, 11, deposit(2, "AAPL", 10))
+	l.Close()
+
+	report, err := Reconcile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report.Compare(map[string]map[string]int64{
+		UserAccount(1): {"CASH": 1000},
+		UserAccount(2): {"AAPL": 10, "CASH": 0},
+	})
+	if len(report.Problems) != 0 {
+		t.Fatalf("Expected the ledger to agree with the accounts, but got %q", report.Problems)
+	}
+
+	// The trade moved the balances, but never made it into the ledger.
+	report.Compare(map[string]map[string]int64{
+		UserAccount(1): {"CASH": 600, "AAPL": 4},
+		UserAccount(2): {"CASH": 400, "AAPL": 6},
+	})
+	if len(report.Problems) != 4 || report.Problems[0] != "user:1 has 0 AAPL in the ledger, but 4 in its account" {
+		t.Errorf("Expected every balance the trade moved to differ, but got %q", report.Problems)
+	}
+}
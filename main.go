@@ -1,27 +1,87 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	pb "github.com/MichalPitr/exchange/protos"
 	"google.golang.org/grpc"
 
+	"github.com/MichalPitr/exchange/accounts"
 	"github.com/MichalPitr/exchange/engine"
+	"github.com/MichalPitr/exchange/instrument"
+	"github.com/MichalPitr/exchange/reporter"
 )
 
 func main() {
+	instrumentsFile := flag.String("instruments", "instruments.json", "JSON file listing the instruments traded on the exchange")
+	dataDir := flag.String("data-dir", "data", "Directory for journals, snapshots and trade logs")
+	snapshotEvery := flag.Uint64("snapshot-every", 100000, "Snapshot a market after this many commands, 0 disables")
+	snapshotInterval := flag.Duration("snapshot-interval", time.Minute, "Snapshot markets that changed this often, 0 disables")
+	tradeLogs := flag.String("trade-logs", reporter.CSV, "Comma separated formats of the trade logs written per market: csv, jsonl or binary")
+	tradeLogFsync := flag.String("trade-log-fsync", "batch", "When trade logs are fsynced: batch, never, or a duration such as 100ms")
+	tradeLogSegmentSize := flag.Int64("trade-log-segment-size", 64<<20, "Rotate trade log segments at this many bytes, 0 disables")
+	tradeLogSegmentAge := flag.Duration("trade-log-segment-age", 24*time.Hour, "Rotate trade log segments this old, 0 disables")
+	checkBalances := flag.Bool("accounts", true, "Check orders against the balances of their users, false lets anyone trade any amount")
+	sessionClose := flag.String("session-close", "00:00", "Time of day (HH:MM, UTC) at which the trading session closes and DAY orders expire")
+	flag.Parse()
+
+	closeTime, err := time.Parse("15:04", *sessionClose)
+	if err != nil {
+		log.Fatalf("Invalid session close %q: %v", *sessionClose, err)
+	}
+
+	fsync, fsyncInterval, err := reporter.ParseFsyncPolicy(*tradeLogFsync)
+	if err != nil {
+		log.Fatalf("Invalid trade log fsync: %v", err)
+	}
+	tradeLogOptions := reporter.Options{
+		Fsync:          fsync,
+		FsyncInterval:  fsyncInterval,
+		MaxSegmentSize: *tradeLogSegmentSize,
+		MaxSegmentAge:  *tradeLogSegmentAge,
+	}
+
+	instruments, err := instrument.Load(*instrumentsFile)
+	if err != nil {
+		log.Fatalf("Failed to load instruments: %v", err)
+	}
+
 	// Run your program here
 	lis, err := net.Listen("tcp", ":50051")
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 	s := grpc.NewServer()
-	e, err := engine.New(1000)
+	if err := os.MkdirAll(*dataDir, 0777); err != nil {
+		log.Fatalf("Failed to create data directory: %v", err)
+	}
+	var userAccounts *accounts.Accounts
+	if *checkBalances {
+		if userAccounts, err = accounts.Open(filepath.Join(*dataDir, "accounts.journal"), filepath.Join(*dataDir, "ledger.journal")); err != nil {
+			log.Fatalf("Failed to open accounts: %v", err)
+		}
+	}
+	e, err := engine.New(engine.Config{
+		QueueSize:        1000,
+		Instruments:      instruments,
+		DataDir:          *dataDir,
+		SnapshotEvery:    *snapshotEvery,
+		SnapshotInterval: *snapshotInterval,
+		SessionClose:     time.Duration(closeTime.Hour())*time.Hour + time.Duration(closeTime.Minute())*time.Minute,
+		Accounts:         userAccounts,
+		TradeSink: func(symbol string) (reporter.Sink, error) {
+			return reporter.OpenAll(strings.Split(*tradeLogs, ","), filepath.Join(*dataDir, "trades_"+symbol), tradeLogOptions)
+		},
+	})
 	if err != nil {
 		log.Fatalf("Failed to start engine: %v", err)
 	}
@@ -49,8 +109,12 @@ func main() {
 		<-sigChan // Wait for interrupt signal
 		log.Println("Shutting down the server...")
 
+		e.EndSubscriptions()
 		s.GracefulStop()
 		e.Close()
+		if userAccounts != nil {
+			userAccounts.Close()
+		}
 
 		wg.Done()
 	}()
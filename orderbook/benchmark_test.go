@@ -0,0 +1,123 @@
+package orderbook
+
+import (
+	"container/heap"
+	"math/rand"
+	"testing"
+)
+
+// book is what the benchmarks need from Book and heapBook.
+type book interface {
+	Add(order Order)
+	Peek() (*Order, bool)
+	Remove(id uint64) (Order, bool)
+	Fill(order *Order, amount int32)
+	Len() int
+}
+
+type heapAdapter struct{ *heapBook }
+
+func (b heapAdapter) Add(order Order) { heap.Push(b.heapBook, &heapItem{Order: order}) }
+
+var implementations = []struct {
+	name string
+	new  func() book
+}{
+	{"levels", func() book { return New(true) }},
+	{"heap", func() book { return heapAdapter{newHeapBook(true)} }},
+}
+
+// Books in the benchmarks hold this many orders spread over this many prices.
+const (
+	benchmarkOrders = 10000
+	benchmarkPrices = 200
+)
+
+func randomOrder(r *rand.Rand, id uint64) Order {
+	return Order{Id: id, Type: Sell, OrderType: Limit, Amount: 1 + r.Int31n(100), Price: 1000 + r.Int63n(benchmarkPrices), Time: int64(id)}
+}
+
+func filledBook(newBook func() book, r *rand.Rand) book {
+	b := newBook()
+	for id := uint64(0); id < benchmarkOrders; id++ {
+		b.Add(randomOrder(r, id))
+	}
+	return b
+}
+
+func BenchmarkInsert(b *testing.B) {
+	for _, impl := range implementations {
+		b.Run(impl.name, func(b *testing.B) {
+			r := rand.New(rand.NewSource(1))
+			book := filledBook(impl.new, r)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				id := uint64(benchmarkOrders + i)
+				book.Add(randomOrder(r, id))
+				b.StopTimer()
+				book.Remove(id)
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+// BenchmarkMatch takes amounts off the best order like an incoming order does, replacing the orders it fills.
+func BenchmarkMatch(b *testing.B) {
+	for _, impl := range implementations {
+		b.Run(impl.name, func(b *testing.B) {
+			r := rand.New(rand.NewSource(1))
+			book := filledBook(impl.new, r)
+			next := uint64(benchmarkOrders)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				top, _ := book.Peek()
+				if top.Amount > 10 {
+					book.Fill(top, 10)
+					continue
+				}
+				book.Remove(top.Id)
+				book.Add(randomOrder(r, next))
+				next++
+			}
+		})
+	}
+}
+
+func BenchmarkCancel(b *testing.B) {
+	for _, impl := range implementations {
+		b.Run(impl.name, func(b *testing.B) {
+			r := rand.New(rand.NewSource(1))
+			book := filledBook(impl.new, r)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				order, _ := book.Remove(uint64(r.Int63n(benchmarkOrders)))
+				b.StopTimer()
+				book.Add(order)
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+// Deep books in the benchmarks hold one order at each of this many prices.
+const benchmarkLevels = 50000
+
+// BenchmarkLevelChurn cancels the only order at a random price of a deep book, and places it again, so that every
+// iteration removes a price level and adds one back.
+func BenchmarkLevelChurn(b *testing.B) {
+	for _, impl := range implementations {
+		b.Run(impl.name, func(b *testing.B) {
+			book := impl.new()
+			for id := uint64(0); id < benchmarkLevels; id++ {
+				book.Add(Order{Id: id, Type: Sell, OrderType: Limit, Amount: 10, Price: 1000 + int64(id), Time: int64(id)})
+			}
+			r := rand.New(rand.NewSource(1))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				order, _ := book.Remove(uint64(r.Int63n(benchmarkLevels)))
+				book.Add(order)
+			}
+		})
+	}
+}
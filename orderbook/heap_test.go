@@ -0,0 +1,91 @@
+package orderbook
+
+import "container/heap"
+
+// heapBook is the binary heap of all orders that Book replaced, kept to benchmark against.
+type heapBook struct {
+	orders []*heapItem
+	byId   map[uint64]*heapItem
+	asc    bool
+	levels map[int64]*Level
+}
+
+type heapItem struct {
+	Order Order
+	index int
+}
+
+func newHeapBook(asc bool) *heapBook {
+	return &heapBook{asc: asc, byId: make(map[uint64]*heapItem), levels: make(map[int64]*Level)}
+}
+
+func (b heapBook) Len() int { return len(b.orders) }
+
+func (b heapBook) Less(i, j int) bool {
+	if b.orders[i].Order.Price == b.orders[j].Order.Price {
+		return b.orders[i].Order.Time < b.orders[j].Order.Time
+	}
+	if b.asc {
+		return b.orders[i].Order.Price < b.orders[j].Order.Price
+	}
+	return b.orders[i].Order.Price > b.orders[j].Order.Price
+}
+
+func (b heapBook) Swap(i, j int) {
+	b.orders[i], b.orders[j] = b.orders[j], b.orders[i]
+	b.orders[i].index = i
+	b.orders[j].index = j
+}
+
+func (b *heapBook) Push(x any) {
+	item := x.(*heapItem)
+	item.index = len(b.orders)
+	b.orders = append(b.orders, item)
+	b.byId[item.Order.Id] = item
+	b.adjustLevel(item.Order.Price, int64(item.Order.Amount), 1)
+}
+
+func (b *heapBook) Pop() any {
+	n := len(b.orders)
+	item := b.orders[n-1]
+	b.orders[n-1] = nil
+	b.orders = b.orders[:n-1]
+	delete(b.byId, item.Order.Id)
+	b.adjustLevel(item.Order.Price, -int64(item.Order.Amount), -1)
+	return item
+}
+
+func (b *heapBook) Peek() (*Order, bool) {
+	if len(b.orders) == 0 {
+		return &Order{}, false
+	}
+	return &b.orders[0].Order, true
+}
+
+func (b *heapBook) Remove(id uint64) (Order, bool) {
+	item, ok := b.byId[id]
+	if !ok {
+		return Order{}, false
+	}
+	heap.Remove(b, item.index)
+	return item.Order, true
+}
+
+func (b *heapBook) Fill(order *Order, amount int32) {
+	order.Amount -= amount
+	order.Filled += amount
+	b.adjustLevel(order.Price, -int64(amount), 0)
+}
+
+func (b *heapBook) adjustLevel(price int64, amount int64, count int) {
+	level, ok := b.levels[price]
+	if !ok {
+		level = &Level{Price: price}
+		b.levels[price] = level
+	}
+	level.Amount += amount
+	level.Count += count
+	if level.Count == 0 {
+		delete(b.levels, price)
+	}
+}
@@ -1,84 +1,389 @@
 package orderbook
 
 import (
-	"container/heap"
 	"log"
+	"sort"
+)
+
+type Side string
+
+const (
+	Buy  Side = "BUY"
+	Sell Side = "SELL"
+)
+
+type OrderType string
+
+const (
+	Limit     OrderType = "LIMIT"
+	Market    OrderType = "MARKET"
+	Stop      OrderType = "STOP"
+	StopLimit OrderType = "STOP_LIMIT"
+)
+
+type TimeInForce string
+
+// Orders without a time in force are GTC if they are LIMIT, and IOC if they are MARKET orders.
+const (
+	GTC TimeInForce = "GTC"
+	IOC TimeInForce = "IOC"
+	FOK TimeInForce = "FOK"
+	Day TimeInForce = "DAY"
+	GTD TimeInForce = "GTD"
+)
+
+// PostOnly tells what happens to an order that would take liquidity. Orders without it are not post-only.
+type PostOnly string
+
+const (
+	PostOnlyReject  PostOnly = "REJECT"
+	PostOnlyReprice PostOnly = "REPRICE"
+)
+
+// SelfTradePrevention tells which orders are cancelled when an order meets one of the same trader. Orders without it
+// trade with their own.
+type SelfTradePrevention string
+
+const (
+	CancelNewest       SelfTradePrevention = "CANCEL_NEWEST"
+	CancelOldest       SelfTradePrevention = "CANCEL_OLDEST"
+	CancelBoth         SelfTradePrevention = "CANCEL_BOTH"
+	DecrementAndCancel SelfTradePrevention = "DECREMENT_AND_CANCEL"
 )
 
 type Order struct {
-	Id         uint64
-	UserID     int32
-	Type       string // BUY or SELL
-	OrderType  string // MARKET or LIMIT
-	Amount     int32
-	Price      int64
-	Time       int64
-	ResultChan chan OrderResult
+	Id        uint64
+	UserID    int32
+	Symbol    string
+	Type      Side
+	OrderType OrderType
+	Amount    int32 // Open amount, reduced as the order fills.
+	Price     int64
+	Time      int64
+	Filled    int32 // Amount executed so far.
+
+	MaxSlippageTicks int32 // MARKET orders stop matching this many ticks past the best price on arrival, 0 for no limit.
+	TimeInForce      TimeInForce
+	ExpireTime       int64 // When DAY and GTD orders expire, 0 for orders that don't.
+	StopPrice        int64 // Trade price that releases STOP and STOP_LIMIT orders.
+	DisplayAmount    int32 // Size of the slices iceberg orders show, 0 for orders that show their whole amount.
+	Hidden           int32 // Amount of an iceberg order behind its resting slice.
+	PostOnly         PostOnly
+	Budget           int64 // Cash that a MARKET or STOP buy order may still spend, when balances are checked.
+
+	SelfTradePrevention SelfTradePrevention
+	SelfTradeGroup      int32 // Orders of the same non-zero group count as orders of the same user.
 }
 
-type Item struct {
-	Order Order
+// Level is the aggregate of all orders resting at a price.
+type Level struct {
+	Price  int64
+	Amount int64
+	Count  int
+}
 
-	// Necessary for heap.interface methods
-	index int
+// entry is a resting order, linked into the queue of its price level.
+type entry struct {
+	order      Order
+	level      *priceLevel
+	prev, next *entry
 }
 
-type OrderResult struct {
-	Success bool
-	Message string
+// priceLevel is the queue of orders resting at a price, oldest first, together with their aggregate.
+type priceLevel struct {
+	Level
+	head, tail    *entry
+	better, worse *priceLevel // Neighbouring levels, in the list of levels from the best price to the worst.
+	left, right   *priceLevel // Children in the tree of levels, which holds better prices on the left.
+	priority      uint64      // Levels with higher priority are closer to the root of the tree.
 }
 
+// Book holds the resting orders of one side of a market. Orders are grouped into price levels, and trade in arrival
+// order within their level. Levels are linked into a list sorted by price, which the best level is taken from and
+// iterated in O(1) per level. They're also kept in a treap, which finds the neighbours of a new level and removes an
+// emptied one in O(log levels) without moving the others.
 type Book struct {
-	orders []*Item
-	asc    bool // Denotes if orders are ordered in asc or desc order. asc is for Sells, desc is for Buys
+	asc     bool                  // Denotes if the best price is the lowest one. asc is for Sells, desc is for Buys
+	best    *priceLevel           // Head of the list of levels.
+	root    *priceLevel           // Root of the tree of levels.
+	levels  map[int64]*priceLevel // Index of levels by price.
+	byId    map[uint64]*entry     // Index of resting orders by id, allows removal without scanning.
+	count   int
+	changed map[int64]struct{} // Prices whose level changed since the last TakeChanges.
+}
+
+func New(asc bool) *Book {
+	return &Book{
+		asc:     asc,
+		levels:  make(map[int64]*priceLevel),
+		byId:    make(map[uint64]*entry),
+		changed: make(map[int64]struct{}),
+	}
 }
 
-func (b Book) Len() int { return len(b.orders) }
+// Len returns the number of resting orders.
+func (b *Book) Len() int { return b.count }
 
-func (b Book) Less(i, j int) bool {
-	// Min-heap if asc is true, else max-heap
+// better tells whether price x is better than price y for the side of the book.
+func (b *Book) better(x, y int64) bool {
 	if b.asc {
-		if b.orders[i].Order.Price == b.orders[j].Order.Price {
-			return b.orders[i].Order.Time < b.orders[j].Order.Time
+		return x < y
+	}
+	return x > y
+}
+
+// Add rests order behind the orders already at its price.
+func (b *Book) Add(order Order) {
+	level, ok := b.levels[order.Price]
+	if !ok {
+		level = &priceLevel{Level: Level{Price: order.Price}, priority: priority(order.Price)}
+		b.levels[order.Price] = level
+		b.insertLevel(level)
+	}
+
+	e := &entry{order: order, level: level, prev: level.tail}
+	if level.tail != nil {
+		level.tail.next = e
+	} else {
+		level.head = e
+	}
+	level.tail = e
+	b.byId[order.Id] = e
+	b.count++
+	b.adjustLevel(level, int64(order.Amount), 1)
+}
+
+// Peek returns the order with the best price that arrived first, which trades next.
+func (b *Book) Peek() (*Order, bool) {
+	if b.count == 0 {
+		log.Println("Peeking empty orderbook.")
+		return &Order{}, false
+	}
+	return &b.best.head.order, true
+}
+
+// Pop takes the order returned by Peek out of the book.
+func (b *Book) Pop() (Order, bool) {
+	if b.count == 0 {
+		return Order{}, false
+	}
+	e := b.best.head
+	b.unlink(e)
+	return e.order, true
+}
+
+// Get returns the resting order with the given id.
+func (b *Book) Get(id uint64) (*Order, bool) {
+	e, ok := b.byId[id]
+	if !ok {
+		return nil, false
+	}
+	return &e.order, true
+}
+
+// Remove takes the order with the given id out of the book in O(1), or in O(log levels) if it's the last one at its
+// price.
+func (b *Book) Remove(id uint64) (Order, bool) {
+	e, ok := b.byId[id]
+	if !ok {
+		return Order{}, false
+	}
+	b.unlink(e)
+	return e.order, true
+}
+
+func (b *Book) unlink(e *entry) {
+	level := e.level
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		level.head = e.next
+	}
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		level.tail = e.prev
+	}
+	if b.byId[e.order.Id] == e {
+		delete(b.byId, e.order.Id)
+	}
+	b.count--
+	b.adjustLevel(level, -int64(e.order.Amount), -1)
+
+	if level.Count == 0 {
+		delete(b.levels, level.Price)
+		b.removeLevel(level)
+	}
+}
+
+// insertLevel links a new level into the list and the tree of levels.
+func (b *Book) insertLevel(level *priceLevel) {
+	var better, worse *priceLevel
+	for n := b.root; n != nil; {
+		if b.better(level.Price, n.Price) {
+			worse, n = n, n.left
+		} else {
+			better, n = n, n.right
 		}
-		return b.orders[i].Order.Price < b.orders[j].Order.Price
 	}
+	level.better, level.worse = better, worse
+	if better != nil {
+		better.worse = level
+	} else {
+		b.best = level
+	}
+	if worse != nil {
+		worse.better = level
+	}
+	b.root = b.insert(b.root, level)
+}
 
-	if b.orders[i].Order.Price == b.orders[j].Order.Price {
-		return b.orders[i].Order.Time < b.orders[j].Order.Time
+func (b *Book) insert(root, level *priceLevel) *priceLevel {
+	if root == nil {
+		return level
+	}
+	if b.better(level.Price, root.Price) {
+		root.left = b.insert(root.left, level)
+		if root.left.priority > root.priority {
+			left := root.left
+			root.left, left.right = left.right, root
+			root = left
+		}
+	} else {
+		root.right = b.insert(root.right, level)
+		if root.right.priority > root.priority {
+			right := root.right
+			root.right, right.left = right.left, root
+			root = right
+		}
 	}
-	return b.orders[i].Order.Price > b.orders[j].Order.Price
+	return root
 }
 
-func (b Book) Swap(i, j int) { b.orders[i], b.orders[j] = b.orders[j], b.orders[i] }
+// removeLevel unlinks an emptied level from the list and the tree of levels.
+func (b *Book) removeLevel(level *priceLevel) {
+	if level.better != nil {
+		level.better.worse = level.worse
+	} else {
+		b.best = level.worse
+	}
+	if level.worse != nil {
+		level.worse.better = level.better
+	}
+	b.root = b.remove(b.root, level)
+	level.better, level.worse, level.left, level.right = nil, nil, nil, nil
+}
 
-func (b *Book) Push(x any) {
-	n := len(b.orders)
-	item := x.(Item)
-	item.index = n
-	b.orders = append(b.orders, &item)
+func (b *Book) remove(root, level *priceLevel) *priceLevel {
+	switch {
+	case root == level:
+		return merge(level.left, level.right)
+	case b.better(level.Price, root.Price):
+		root.left = b.remove(root.left, level)
+	default:
+		root.right = b.remove(root.right, level)
+	}
+	return root
 }
 
-func (b *Book) Pop() any {
-	old := *b
-	n := len(old.orders)
-	item := old.orders[n-1]
-	old.orders[n-1] = nil // avoid memory leak
-	item.index = -1       // for safety
-	(*b).orders = old.orders[0 : n-1]
-	return item
+// merge joins two trees of levels, where all levels of left have better prices than those of right.
+func merge(left, right *priceLevel) *priceLevel {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	if left.priority > right.priority {
+		left.right = merge(left.right, right)
+		return left
+	}
+	right.left = merge(left, right.left)
+	return right
 }
 
-func New(asc bool) *Book {
-	b := &Book{asc: asc}
-	heap.Init(b)
-	return b
+// priority scrambles price, so that the tree of levels stays balanced whatever the prices are without depending on a
+// random source.
+func priority(price int64) uint64 {
+	z := uint64(price) + 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
 }
 
-func (b Book) Peek() (*Order, bool) {
-	if b.Len() == 0 {
-		log.Println("Peeking empty orderbook.")
-		return &Order{}, false
+// Orders returns a copy of all resting orders in the order they trade, so adding them to an empty book restores it.
+func (b *Book) Orders() []Order {
+	orders := make([]Order, 0, b.count)
+	b.Each(func(_ Level, level []Order) bool {
+		orders = append(orders, level...)
+		return true
+	})
+	return orders
+}
+
+// Each calls fn with every price level and the orders resting there, best price first, until fn returns false.
+func (b *Book) Each(fn func(level Level, orders []Order) bool) {
+	for level := b.best; level != nil; level = level.worse {
+		orders := make([]Order, 0, level.Count)
+		for e := level.head; e != nil; e = e.next {
+			orders = append(orders, e.order)
+		}
+		if !fn(level.Level, orders) {
+			return
+		}
+	}
+}
+
+// Reduce takes amount off a resting order, as returned by Peek or Get, without changing its priority. It's up to the
+// caller to remove orders that are reduced to nothing.
+func (b *Book) Reduce(order *Order, amount int32) {
+	order.Amount -= amount
+	b.adjustLevel(b.levels[order.Price], -int64(amount), 0)
+}
+
+// Fill executes amount of a resting order, as returned by Peek or Get, without changing its priority. It's up to the
+// caller to remove orders that are filled completely.
+func (b *Book) Fill(order *Order, amount int32) {
+	b.Reduce(order, amount)
+	order.Filled += amount
+}
+
+func (b *Book) adjustLevel(level *priceLevel, amount int64, count int) {
+	level.Amount += amount
+	level.Count += count
+	b.changed[level.Price] = struct{}{}
+}
+
+// Level returns the aggregate of the orders resting at price, which is empty if there are none.
+func (b *Book) Level(price int64) Level {
+	if level, ok := b.levels[price]; ok {
+		return level.Level
+	}
+	return Level{Price: price}
+}
+
+// Depth returns up to n price levels starting at the best price, or all levels if n <= 0.
+func (b *Book) Depth(n int) []Level {
+	if n <= 0 || n > len(b.levels) {
+		n = len(b.levels)
+	}
+	levels := make([]Level, 0, n)
+	for level := b.best; len(levels) < n; level = level.worse {
+		levels = append(levels, level.Level)
+	}
+	return levels
+}
+
+// TakeChanges returns the current state of every level that changed since the previous call, ordered by price.
+func (b *Book) TakeChanges() []Level {
+	if len(b.changed) == 0 {
+		return nil
+	}
+	levels := make([]Level, 0, len(b.changed))
+	for price := range b.changed {
+		levels = append(levels, b.Level(price))
+		delete(b.changed, price)
 	}
-	return &b.orders[0].Order, true
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Price < levels[j].Price })
+	return levels
 }
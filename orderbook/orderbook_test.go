@@ -1,8 +1,9 @@
 package orderbook
 
 import (
-	"container/heap"
 	"fmt"
+	"math/rand"
+	"sort"
 	"testing"
 )
 
@@ -12,60 +13,56 @@ func TestBuyOrderbook(t *testing.T) {
 	// Example orders
 	orders := []Order{
 		{
-			UserID:     1,
-			Type:       "BUY",
-			OrderType:  "LIMIT",
-			Amount:     10,
-			Price:      100,
-			Time:       1641016800, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    1,
+			Type:      "BUY",
+			OrderType: "LIMIT",
+			Amount:    10,
+			Price:     100,
+			Time:      1641016800, // Example Unix timestamp
 		},
 		{
-			UserID:     2,
-			Type:       "BUY",
-			OrderType:  "LIMIT",
-			Amount:     5,
-			Price:      200,
-			Time:       1641016800, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    2,
+			Type:      "BUY",
+			OrderType: "LIMIT",
+			Amount:    5,
+			Price:     200,
+			Time:      1641016800, // Example Unix timestamp
 		},
 		{
-			UserID:     3,
-			Type:       "BUY",
-			OrderType:  "LIMIT",
-			Amount:     15,
-			Price:      50,
-			Time:       1641016800, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    3,
+			Type:      "BUY",
+			OrderType: "LIMIT",
+			Amount:    15,
+			Price:     50,
+			Time:      1641016800, // Example Unix timestamp
 		},
 		{
-			UserID:     4,
-			Type:       "BUY",
-			OrderType:  "LIMIT",
-			Amount:     15,
-			Price:      300,
-			Time:       1641016800, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    4,
+			Type:      "BUY",
+			OrderType: "LIMIT",
+			Amount:    15,
+			Price:     300,
+			Time:      1641016800, // Example Unix timestamp
 		},
 	}
 
 	expectPrice := []int64{300, 200, 100, 50}
 
-	// Add items to both heaps
+	// Add orders to the book
 	for _, order := range orders {
-		heap.Push(buyBook, Item{Order: order})
+		buyBook.Add(order)
 	}
 
-	// Pop items from both heaps
+	// Pop orders best price first
 	fmt.Println("\nbuy book:")
 	for _, p := range expectPrice {
 		order, _ := buyBook.Peek()
 		if order.Price != p {
 			t.Errorf("Expected peek price: %d but got %d\n", p, order.Price)
 		}
-		item := heap.Pop(buyBook).(*Item)
-		if item.Order.Price != p {
-			t.Errorf("Expected price: %d but got %d\n", p, item.Order.Price)
+		popped, _ := buyBook.Pop()
+		if popped.Price != p {
+			t.Errorf("Expected price: %d but got %d\n", p, popped.Price)
 		}
 	}
 }
@@ -76,51 +73,197 @@ func TestSellOrderbook(t *testing.T) {
 	// Example orders
 	orders := []Order{
 		{
-			UserID:     1,
-			Type:       "SELL",
-			OrderType:  "LIMIT",
-			Amount:     10,
-			Price:      100,
-			Time:       1641016800, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    1,
+			Type:      "SELL",
+			OrderType: "LIMIT",
+			Amount:    10,
+			Price:     100,
+			Time:      1641016800, // Example Unix timestamp
 		},
 		{
-			UserID:     2,
-			Type:       "SELL",
-			OrderType:  "LIMIT",
-			Amount:     5,
-			Price:      200,
-			Time:       1641103200, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    2,
+			Type:      "SELL",
+			OrderType: "LIMIT",
+			Amount:    5,
+			Price:     200,
+			Time:      1641103200, // Example Unix timestamp
 		},
 		{
-			UserID:     3,
-			Type:       "SELL",
-			OrderType:  "LIMIT",
-			Amount:     15,
-			Price:      50,
-			Time:       1641189600, // Example Unix timestamp
-			ResultChan: nil,        // Or initialize as appropriate
+			UserID:    3,
+			Type:      "SELL",
+			OrderType: "LIMIT",
+			Amount:    15,
+			Price:     50,
+			Time:      1641189600, // Example Unix timestamp
 		},
 	}
 
 	expectPrice := []int64{50, 100, 200}
 
-	// Add items to both heaps
+	// Add orders to the book
 	for _, order := range orders {
-		heap.Push(sellbook, Item{Order: order})
+		sellbook.Add(order)
 	}
 
-	// Pop items from both heaps
+	// Pop orders best price first
 	fmt.Println("\nsell book:")
 	for _, p := range expectPrice {
 		order, _ := sellbook.Peek()
 		if order.Price != p {
 			t.Errorf("Expected peek price: %d but got %d\n", p, order.Price)
 		}
-		item := heap.Pop(sellbook).(*Item)
-		if item.Order.Price != p {
-			t.Errorf("Expected top price: %d but got %d\n", p, item.Order.Price)
+		popped, _ := sellbook.Pop()
+		if popped.Price != p {
+			t.Errorf("Expected top price: %d but got %d\n", p, popped.Price)
 		}
 	}
 }
+
+func TestRemoveOrder(t *testing.T) {
+	sellbook := New(true)
+
+	orders := []Order{
+		{Id: 1, UserID: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 100, Time: 1641016800},
+		{Id: 2, UserID: 2, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: 200, Time: 1641103200},
+		{Id: 3, UserID: 3, Type: "SELL", OrderType: "LIMIT", Amount: 15, Price: 50, Time: 1641189600},
+		{Id: 4, UserID: 4, Type: "SELL", OrderType: "LIMIT", Amount: 15, Price: 150, Time: 1641189600},
+	}
+	for _, order := range orders {
+		sellbook.Add(order)
+	}
+
+	removed, ok := sellbook.Remove(1)
+	if !ok || removed.Price != 100 {
+		t.Fatalf("Expected to remove order 1 with price 100, got %v (%t)", removed, ok)
+	}
+	if _, ok := sellbook.Get(1); ok {
+		t.Error("Removed order is still indexed.")
+	}
+	if _, ok := sellbook.Remove(1); ok {
+		t.Error("Removing an order twice should fail.")
+	}
+
+	expectPrice := []int64{50, 150, 200}
+	for _, p := range expectPrice {
+		popped, _ := sellbook.Pop()
+		if popped.Price != p {
+			t.Errorf("Expected top price: %d but got %d\n", p, popped.Price)
+		}
+	}
+	if sellbook.Len() != 0 {
+		t.Errorf("Expected empty book, but has %d orders", sellbook.Len())
+	}
+}
+
+func TestLevels(t *testing.T) {
+	buyBook := New(false)
+
+	orders := []Order{
+		{Id: 1, UserID: 1, Type: "BUY", OrderType: "LIMIT", Amount: 10, Price: 100, Time: 1641016800},
+		{Id: 2, UserID: 2, Type: "BUY", OrderType: "LIMIT", Amount: 5, Price: 100, Time: 1641103200},
+		{Id: 3, UserID: 3, Type: "BUY", OrderType: "LIMIT", Amount: 15, Price: 90, Time: 1641189600},
+		{Id: 4, UserID: 4, Type: "BUY", OrderType: "LIMIT", Amount: 20, Price: 80, Time: 1641189600},
+	}
+	for _, order := range orders {
+		buyBook.Add(order)
+	}
+	if changes := buyBook.TakeChanges(); len(changes) != 3 {
+		t.Errorf("Expected 3 changed levels, but got %v", changes)
+	}
+
+	depth := buyBook.Depth(2)
+	if len(depth) != 2 || depth[0] != (Level{100, 15, 2}) || depth[1] != (Level{90, 15, 1}) {
+		t.Errorf("Expected the best 2 levels, but got %v", depth)
+	}
+
+	order, _ := buyBook.Get(1)
+	buyBook.Reduce(order, 4)
+	buyBook.Remove(3)
+	changes := buyBook.TakeChanges()
+	if len(changes) != 2 || changes[0] != (Level{90, 0, 0}) || changes[1] != (Level{100, 11, 2}) {
+		t.Errorf("Expected level 90 to be gone and 100 reduced, but got %v", changes)
+	}
+	if changes := buyBook.TakeChanges(); len(changes) != 0 {
+		t.Errorf("Expected no changes after taking them, but got %v", changes)
+	}
+}
+
+func TestTimePriority(t *testing.T) {
+	sellbook := New(true)
+	for _, order := range []Order{
+		{Id: 1, Type: "SELL", OrderType: "LIMIT", Amount: 10, Price: 100},
+		{Id: 2, Type: "SELL", OrderType: "LIMIT", Amount: 5, Price: 90},
+		{Id: 3, Type: "SELL", OrderType: "LIMIT", Amount: 7, Price: 100},
+		{Id: 4, Type: "SELL", OrderType: "LIMIT", Amount: 3, Price: 100},
+	} {
+		sellbook.Add(order)
+	}
+	sellbook.Remove(3)
+
+	var ids []uint64
+	sellbook.Each(func(level Level, orders []Order) bool {
+		for _, order := range orders {
+			ids = append(ids, order.Id)
+		}
+		return level.Price < 100
+	})
+	if fmt.Sprint(ids) != "[2 1 4]" {
+		t.Errorf("Expected orders best price first in arrival order, but got %v", ids)
+	}
+	if level := sellbook.Level(100); level != (Level{100, 13, 2}) {
+		t.Errorf("Expected 2 orders left at 100, but got %v", level)
+	}
+}
+
+func TestLevelsStaySorted(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	buyBook := New(false)
+	resting := make(map[uint64]int64)
+	for id := uint64(0); id < 2000; id++ {
+		if len(resting) > 0 && r.Intn(3) == 0 {
+			for removed := range resting {
+				buyBook.Remove(removed)
+				delete(resting, removed)
+				break
+			}
+		}
+		price := r.Int63n(300)
+		buyBook.Add(Order{Id: id, Type: "BUY", OrderType: "LIMIT", Amount: 1, Price: price})
+		resting[id] = price
+	}
+
+	prices := make(map[int64]bool)
+	for _, price := range resting {
+		prices[price] = true
+	}
+	var want []int64
+	for price := range prices {
+		want = append(want, price)
+	}
+	sort.Slice(want, func(i, j int) bool { return want[i] > want[j] })
+	var got []int64
+	for _, level := range buyBook.Depth(0) {
+		got = append(got, level.Price)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected levels %v, but got %v", want, got)
+	}
+	if height := treeHeight(buyBook.root); height > 4*bitLength(len(want)) {
+		t.Errorf("Expected a balanced tree of %d levels, but its height is %d", len(want), height)
+	}
+}
+
+func treeHeight(level *priceLevel) int {
+	if level == nil {
+		return 0
+	}
+	return 1 + max(treeHeight(level.left), treeHeight(level.right))
+}
+
+func bitLength(n int) int {
+	bits := 0
+	for ; n > 0; n >>= 1 {
+		bits++
+	}
+	return bits
+}
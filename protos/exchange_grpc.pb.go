@@ -19,7 +19,17 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	OrderService_SendOrder_FullMethodName = "/exchange.OrderService/SendOrder"
+	OrderService_SendOrder_FullMethodName           = "/exchange.OrderService/SendOrder"
+	OrderService_CancelOrder_FullMethodName         = "/exchange.OrderService/CancelOrder"
+	OrderService_AmendOrder_FullMethodName          = "/exchange.OrderService/AmendOrder"
+	OrderService_SubscribeMarketData_FullMethodName = "/exchange.OrderService/SubscribeMarketData"
+	OrderService_SubscribeExecutions_FullMethodName = "/exchange.OrderService/SubscribeExecutions"
+	OrderService_GetOrder_FullMethodName            = "/exchange.OrderService/GetOrder"
+	OrderService_ListOpenOrders_FullMethodName      = "/exchange.OrderService/ListOpenOrders"
+	OrderService_GetOrderBook_FullMethodName        = "/exchange.OrderService/GetOrderBook"
+	OrderService_Deposit_FullMethodName             = "/exchange.OrderService/Deposit"
+	OrderService_Withdraw_FullMethodName            = "/exchange.OrderService/Withdraw"
+	OrderService_GetAccount_FullMethodName          = "/exchange.OrderService/GetAccount"
 )
 
 // OrderServiceClient is the client API for OrderService service.
@@ -28,6 +38,26 @@ const (
 type OrderServiceClient interface {
 	// Sends a new order to the exchange
 	SendOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	// Cancels a resting LIMIT order
+	CancelOrder(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error)
+	// Changes price and/or amount of a resting LIMIT order
+	AmendOrder(ctx context.Context, in *AmendRequest, opts ...grpc.CallOption) (*AmendResponse, error)
+	// Streams public market data of a symbol, starting with a snapshot of its order books
+	SubscribeMarketData(ctx context.Context, in *MarketDataRequest, opts ...grpc.CallOption) (OrderService_SubscribeMarketDataClient, error)
+	// Streams execution reports for the orders of a user
+	SubscribeExecutions(ctx context.Context, in *ExecutionsRequest, opts ...grpc.CallOption) (OrderService_SubscribeExecutionsClient, error)
+	// Returns the current state of an order
+	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*OrderInfo, error)
+	// Returns the orders of a user that are still open
+	ListOpenOrders(ctx context.Context, in *ListOpenOrdersRequest, opts ...grpc.CallOption) (*ListOpenOrdersResponse, error)
+	// Returns the resting orders of a market as they are between two commands
+	GetOrderBook(ctx context.Context, in *OrderBookRequest, opts ...grpc.CallOption) (*OrderBookResponse, error)
+	// Adds cash or holdings of an instrument to the account of a user
+	Deposit(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*AccountInfo, error)
+	// Takes cash or holdings of an instrument out of the account of a user, unless they are reserved for open orders
+	Withdraw(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*AccountInfo, error)
+	// Returns the balances of a user
+	GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*AccountInfo, error)
 }
 
 type orderServiceClient struct {
@@ -47,12 +77,168 @@ func (c *orderServiceClient) SendOrder(ctx context.Context, in *OrderRequest, op
 	return out, nil
 }
 
+func (c *orderServiceClient) CancelOrder(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error) {
+	out := new(CancelResponse)
+	err := c.cc.Invoke(ctx, OrderService_CancelOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) AmendOrder(ctx context.Context, in *AmendRequest, opts ...grpc.CallOption) (*AmendResponse, error) {
+	out := new(AmendResponse)
+	err := c.cc.Invoke(ctx, OrderService_AmendOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) SubscribeMarketData(ctx context.Context, in *MarketDataRequest, opts ...grpc.CallOption) (OrderService_SubscribeMarketDataClient, error) {
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[0], OrderService_SubscribeMarketData_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &orderServiceSubscribeMarketDataClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OrderService_SubscribeMarketDataClient interface {
+	Recv() (*MarketDataUpdate, error)
+	grpc.ClientStream
+}
+
+type orderServiceSubscribeMarketDataClient struct {
+	grpc.ClientStream
+}
+
+func (x *orderServiceSubscribeMarketDataClient) Recv() (*MarketDataUpdate, error) {
+	m := new(MarketDataUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *orderServiceClient) SubscribeExecutions(ctx context.Context, in *ExecutionsRequest, opts ...grpc.CallOption) (OrderService_SubscribeExecutionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[1], OrderService_SubscribeExecutions_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &orderServiceSubscribeExecutionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OrderService_SubscribeExecutionsClient interface {
+	Recv() (*ExecutionReport, error)
+	grpc.ClientStream
+}
+
+type orderServiceSubscribeExecutionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *orderServiceSubscribeExecutionsClient) Recv() (*ExecutionReport, error) {
+	m := new(ExecutionReport)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *orderServiceClient) GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*OrderInfo, error) {
+	out := new(OrderInfo)
+	err := c.cc.Invoke(ctx, OrderService_GetOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListOpenOrders(ctx context.Context, in *ListOpenOrdersRequest, opts ...grpc.CallOption) (*ListOpenOrdersResponse, error) {
+	out := new(ListOpenOrdersResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListOpenOrders_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetOrderBook(ctx context.Context, in *OrderBookRequest, opts ...grpc.CallOption) (*OrderBookResponse, error) {
+	out := new(OrderBookResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetOrderBook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) Deposit(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*AccountInfo, error) {
+	out := new(AccountInfo)
+	err := c.cc.Invoke(ctx, OrderService_Deposit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) Withdraw(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*AccountInfo, error) {
+	out := new(AccountInfo)
+	err := c.cc.Invoke(ctx, OrderService_Withdraw_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*AccountInfo, error) {
+	out := new(AccountInfo)
+	err := c.cc.Invoke(ctx, OrderService_GetAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderServiceServer is the server API for OrderService service.
 // All implementations must embed UnimplementedOrderServiceServer
 // for forward compatibility
 type OrderServiceServer interface {
 	// Sends a new order to the exchange
 	SendOrder(context.Context, *OrderRequest) (*OrderResponse, error)
+	// Cancels a resting LIMIT order
+	CancelOrder(context.Context, *CancelRequest) (*CancelResponse, error)
+	// Changes price and/or amount of a resting LIMIT order
+	AmendOrder(context.Context, *AmendRequest) (*AmendResponse, error)
+	// Streams public market data of a symbol, starting with a snapshot of its order books
+	SubscribeMarketData(*MarketDataRequest, OrderService_SubscribeMarketDataServer) error
+	// Streams execution reports for the orders of a user
+	SubscribeExecutions(*ExecutionsRequest, OrderService_SubscribeExecutionsServer) error
+	// Returns the current state of an order
+	GetOrder(context.Context, *GetOrderRequest) (*OrderInfo, error)
+	// Returns the orders of a user that are still open
+	ListOpenOrders(context.Context, *ListOpenOrdersRequest) (*ListOpenOrdersResponse, error)
+	// Returns the resting orders of a market as they are between two commands
+	GetOrderBook(context.Context, *OrderBookRequest) (*OrderBookResponse, error)
+	// Adds cash or holdings of an instrument to the account of a user
+	Deposit(context.Context, *TransferRequest) (*AccountInfo, error)
+	// Takes cash or holdings of an instrument out of the account of a user, unless they are reserved for open orders
+	Withdraw(context.Context, *TransferRequest) (*AccountInfo, error)
+	// Returns the balances of a user
+	GetAccount(context.Context, *GetAccountRequest) (*AccountInfo, error)
 	mustEmbedUnimplementedOrderServiceServer()
 }
 
@@ -63,6 +249,36 @@ type UnimplementedOrderServiceServer struct {
 func (UnimplementedOrderServiceServer) SendOrder(context.Context, *OrderRequest) (*OrderResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendOrder not implemented")
 }
+func (UnimplementedOrderServiceServer) CancelOrder(context.Context, *CancelRequest) (*CancelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) AmendOrder(context.Context, *AmendRequest) (*AmendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AmendOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) SubscribeMarketData(*MarketDataRequest, OrderService_SubscribeMarketDataServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeMarketData not implemented")
+}
+func (UnimplementedOrderServiceServer) SubscribeExecutions(*ExecutionsRequest, OrderService_SubscribeExecutionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeExecutions not implemented")
+}
+func (UnimplementedOrderServiceServer) GetOrder(context.Context, *GetOrderRequest) (*OrderInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) ListOpenOrders(context.Context, *ListOpenOrdersRequest) (*ListOpenOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOpenOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) GetOrderBook(context.Context, *OrderBookRequest) (*OrderBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderBook not implemented")
+}
+func (UnimplementedOrderServiceServer) Deposit(context.Context, *TransferRequest) (*AccountInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Deposit not implemented")
+}
+func (UnimplementedOrderServiceServer) Withdraw(context.Context, *TransferRequest) (*AccountInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Withdraw not implemented")
+}
+func (UnimplementedOrderServiceServer) GetAccount(context.Context, *GetAccountRequest) (*AccountInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccount not implemented")
+}
 func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
 
 // UnsafeOrderServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -94,6 +310,192 @@ func _OrderService_SendOrder_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CancelOrder(ctx, req.(*CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_AmendOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AmendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).AmendOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_AmendOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).AmendOrder(ctx, req.(*AmendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_SubscribeMarketData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MarketDataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).SubscribeMarketData(m, &orderServiceSubscribeMarketDataServer{stream})
+}
+
+type OrderService_SubscribeMarketDataServer interface {
+	Send(*MarketDataUpdate) error
+	grpc.ServerStream
+}
+
+type orderServiceSubscribeMarketDataServer struct {
+	grpc.ServerStream
+}
+
+func (x *orderServiceSubscribeMarketDataServer) Send(m *MarketDataUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OrderService_SubscribeExecutions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecutionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).SubscribeExecutions(m, &orderServiceSubscribeExecutionsServer{stream})
+}
+
+type OrderService_SubscribeExecutionsServer interface {
+	Send(*ExecutionReport) error
+	grpc.ServerStream
+}
+
+type orderServiceSubscribeExecutionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *orderServiceSubscribeExecutionsServer) Send(m *ExecutionReport) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OrderService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListOpenOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOpenOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListOpenOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListOpenOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListOpenOrders(ctx, req.(*ListOpenOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetOrderBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetOrderBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetOrderBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetOrderBook(ctx, req.(*OrderBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_Deposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).Deposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_Deposit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).Deposit(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_Withdraw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).Withdraw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_Withdraw_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).Withdraw(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetAccount(ctx, req.(*GetAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -105,7 +507,50 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendOrder",
 			Handler:    _OrderService_SendOrder_Handler,
 		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _OrderService_CancelOrder_Handler,
+		},
+		{
+			MethodName: "AmendOrder",
+			Handler:    _OrderService_AmendOrder_Handler,
+		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _OrderService_GetOrder_Handler,
+		},
+		{
+			MethodName: "ListOpenOrders",
+			Handler:    _OrderService_ListOpenOrders_Handler,
+		},
+		{
+			MethodName: "GetOrderBook",
+			Handler:    _OrderService_GetOrderBook_Handler,
+		},
+		{
+			MethodName: "Deposit",
+			Handler:    _OrderService_Deposit_Handler,
+		},
+		{
+			MethodName: "Withdraw",
+			Handler:    _OrderService_Withdraw_Handler,
+		},
+		{
+			MethodName: "GetAccount",
+			Handler:    _OrderService_GetAccount_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeMarketData",
+			Handler:       _OrderService_SubscribeMarketData_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeExecutions",
+			Handler:       _OrderService_SubscribeExecutions_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "exchange.proto",
 }
@@ -0,0 +1,164 @@
+// Command reconcile replays the ledger of the exchange and checks that it conserves balances. Given the data directory
+// of the exchange, it also checks that the ledger agrees with the balances the exchange restores from its journals and
+// snapshots.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/MichalPitr/exchange/accounts"
+	"github.com/MichalPitr/exchange/engine"
+	"github.com/MichalPitr/exchange/instrument"
+	"github.com/MichalPitr/exchange/ledger"
+	"github.com/MichalPitr/exchange/reporter"
+)
+
+func main() {
+	filename := flag.String("ledger", "data/ledger.journal", "Ledger to reconcile")
+	printBalances := flag.Bool("balances", false, "Print the balance of every account")
+	dataDir := flag.String("data-dir", "", "Data directory of the stopped exchange to compare the balances of users with, empty to skip")
+	instrumentsFile := flag.String("instruments", "instruments.json", "JSON file listing the instruments traded on the exchange, with -data-dir")
+	flag.Parse()
+
+	report, err := ledger.Reconcile(*filename)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *filename, err)
+	}
+	if *dataDir != "" {
+		instruments, err := instrument.Load(*instrumentsFile)
+		if err != nil {
+			log.Fatalf("Failed to load instruments: %v", err)
+		}
+		balances, err := accountBalances(*dataDir, *filename, instruments)
+		if err != nil {
+			log.Fatalf("Failed to restore the accounts in %s: %v", *dataDir, err)
+		}
+		report.Compare(balances)
+	}
+
+	fmt.Printf("%d entries, %d accounts\n", report.Entries, len(report.Balances))
+	for _, asset := range sortedKeys(report.Supply) {
+		fmt.Printf("supply %s %d\n", asset, report.Supply[asset])
+	}
+	if *printBalances {
+		for _, account := range sortedKeys(report.Balances) {
+			for _, asset := range sortedKeys(report.Balances[account]) {
+				fmt.Printf("%s %s %d\n", account, asset, report.Balances[account][asset])
+			}
+		}
+	}
+
+	if len(report.Problems) > 0 {
+		for _, problem := range report.Problems {
+			fmt.Println("problem:", problem)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("ledger reconciles")
+}
+
+// accountBalances restores the accounts and markets of the exchange in dataDir the way the exchange does when it
+// starts, and returns the balance of every user account by asset. It works on a copy of the journals and snapshots, as
+// restoring trims torn records, and leaves out the ledger, which is what the balances are compared with. Entries that
+// didn't make it into the ledger before a crash, which the exchange posts when it restarts, show up as differences.
+func accountBalances(dataDir, ledgerFile string, instruments *instrument.Registry) (map[string]map[string]int64, error) {
+	dir, err := os.MkdirTemp("", "reconcile")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := copyState(dataDir, ledgerFile, dir); err != nil {
+		return nil, err
+	}
+
+	userAccounts, err := accounts.Open(filepath.Join(dir, "accounts.journal"), "")
+	if err != nil {
+		return nil, err
+	}
+	defer userAccounts.Close()
+	e, err := engine.New(engine.Config{
+		QueueSize:   1,
+		Instruments: instruments,
+		DataDir:     dir,
+		Accounts:    userAccounts,
+		TradeSink:   func(string) (reporter.Sink, error) { return reporter.NewRing(1), nil },
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer e.Close()
+
+	balances := make(map[string]map[string]int64)
+	for _, userId := range userAccounts.Users() {
+		assets, amounts := userAccounts.Balances(userId)
+		account := make(map[string]int64, len(assets))
+		for i, asset := range assets {
+			account[asset] = amounts[i].Total
+		}
+		balances[ledger.UserAccount(userId)] = account
+	}
+	return balances, nil
+}
+
+// copyState copies the journals and snapshots in dataDir, apart from the ledger, into dir.
+func copyState(dataDir, ledgerFile, dir string) error {
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		source := filepath.Join(dataDir, name)
+		if !entry.Type().IsRegular() || sameFile(source, ledgerFile) {
+			continue
+		}
+		if strings.HasSuffix(name, ".journal") || strings.HasSuffix(name, ".snapshot") {
+			if err := copyFile(source, filepath.Join(dir, name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sameFile(x, y string) bool {
+	xInfo, err := os.Stat(x)
+	if err != nil {
+		return false
+	}
+	yInfo, err := os.Stat(y)
+	return err == nil && os.SameFile(xInfo, yInfo)
+}
+
+func copyFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
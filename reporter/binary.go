@@ -0,0 +1,200 @@
+package reporter
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Kinds of records in binary trade logs.
+const (
+	binaryTrade  byte = 1
+	binaryCancel byte = 2
+	binaryFormat byte = 3 // Starts every segment, with the version of the format as a uint16.
+)
+
+// Version of the binary trade log format.
+const binaryVersion = 1
+
+// Each binary record is framed by its length and CRC32 checksum, like journal records.
+const binaryHeaderSize = 8
+
+// binaryCodec writes framed records. A record is a kind byte followed by the fields of Trade or Cancel in order,
+// little-endian, with strings prefixed by their uint16 length.
+type binaryCodec struct{}
+
+var binaryHeader = func() []byte {
+	b, start := beginFrame(nil, binaryFormat)
+	b = binary.LittleEndian.AppendUint16(b, binaryVersion)
+	return endFrame(b, start)
+}()
+
+func (binaryCodec) ext() string    { return ".bin" }
+func (binaryCodec) header() []byte { return binaryHeader }
+
+func (binaryCodec) trade(b []byte, t Trade) []byte {
+	b, start := beginFrame(b, binaryTrade)
+	b = binary.LittleEndian.AppendUint64(b, t.TradeId)
+	b = binary.LittleEndian.AppendUint64(b, uint64(t.Time))
+	b = appendString(b, t.Symbol)
+	b = appendString(b, t.AggressorSide)
+	b = binary.LittleEndian.AppendUint64(b, t.TakerOrderId)
+	b = binary.LittleEndian.AppendUint32(b, uint32(t.TakerUserId))
+	b = binary.LittleEndian.AppendUint64(b, t.MakerOrderId)
+	b = binary.LittleEndian.AppendUint32(b, uint32(t.MakerUserId))
+	b = binary.LittleEndian.AppendUint32(b, uint32(t.Amount))
+	b = binary.LittleEndian.AppendUint64(b, uint64(t.Price))
+	b = binary.LittleEndian.AppendUint64(b, uint64(t.MakerFee))
+	b = binary.LittleEndian.AppendUint64(b, uint64(t.TakerFee))
+	return endFrame(b, start)
+}
+
+func (binaryCodec) cancel(b []byte, c Cancel) []byte {
+	b, start := beginFrame(b, binaryCancel)
+	b = appendString(b, c.Symbol)
+	b = binary.LittleEndian.AppendUint64(b, c.OrderId)
+	b = binary.LittleEndian.AppendUint32(b, uint32(c.UserId))
+	b = binary.LittleEndian.AppendUint32(b, uint32(c.Amount))
+	b = binary.LittleEndian.AppendUint64(b, uint64(c.Price))
+	return endFrame(b, start)
+}
+
+// beginFrame leaves room for the frame header, and starts the record with its kind.
+func beginFrame(b []byte, kind byte) ([]byte, int) {
+	start := len(b)
+	b = append(b, make([]byte, binaryHeaderSize)...)
+	return append(b, kind), start
+}
+
+// endFrame fills in the frame header of the record that starts at start.
+func endFrame(b []byte, start int) []byte {
+	record := b[start+binaryHeaderSize:]
+	binary.LittleEndian.PutUint32(b[start:], uint32(len(record)))
+	binary.LittleEndian.PutUint32(b[start+4:], crc32.ChecksumIEEE(record))
+	return b
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+func (binaryCodec) intact(data []byte) (int64, uint64) {
+	var length int64
+	var records uint64
+	for {
+		_, rest, ok := nextFrame(data)
+		if !ok {
+			return length, records
+		}
+		length += int64(len(data) - len(rest))
+		records++
+		data = rest
+	}
+}
+
+// nextFrame splits off the first record of data, if it's whole and its checksum matches.
+func nextFrame(data []byte) (record, rest []byte, ok bool) {
+	if len(data) < binaryHeaderSize {
+		return nil, data, false
+	}
+	size := int(binary.LittleEndian.Uint32(data[0:4]))
+	if size == 0 || len(data)-binaryHeaderSize < size {
+		return nil, data, false
+	}
+	record = data[binaryHeaderSize : binaryHeaderSize+size]
+	if crc32.ChecksumIEEE(record) != binary.LittleEndian.Uint32(data[4:8]) {
+		return nil, data, false
+	}
+	return record, data[binaryHeaderSize+size:], true
+}
+
+// ReadBinary decodes the records of a binary trade log segment, skipping its format record.
+func ReadBinary(r io.Reader) ([]Record, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	format, data, ok := nextFrame(data)
+	if !ok || format[0] != binaryFormat {
+		return nil, errors.New("segment doesn't start with a format record")
+	}
+	d := decoder{b: format[1:]}
+	if version := d.uint16(); d.err != nil || version != binaryVersion {
+		return nil, fmt.Errorf("unsupported binary trade log version %d", version)
+	}
+
+	var records []Record
+	for len(data) > 0 {
+		payload, rest, ok := nextFrame(data)
+		if !ok {
+			return records, errors.New("torn or corrupted record")
+		}
+		record, err := decodeBinary(payload)
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+		data = rest
+	}
+	return records, nil
+}
+
+func decodeBinary(payload []byte) (Record, error) {
+	d := decoder{b: payload[1:]}
+	switch payload[0] {
+	case binaryTrade:
+		t := &Trade{
+			TradeId:       d.uint64(),
+			Time:          int64(d.uint64()),
+			Symbol:        d.string(),
+			AggressorSide: d.string(),
+			TakerOrderId:  d.uint64(),
+			TakerUserId:   int32(d.uint32()),
+			MakerOrderId:  d.uint64(),
+			MakerUserId:   int32(d.uint32()),
+			Amount:        int32(d.uint32()),
+			Price:         int64(d.uint64()),
+			MakerFee:      int64(d.uint64()),
+			TakerFee:      int64(d.uint64()),
+		}
+		return Record{Trade: t}, d.err
+	case binaryCancel:
+		c := &Cancel{
+			Symbol:  d.string(),
+			OrderId: d.uint64(),
+			UserId:  int32(d.uint32()),
+			Amount:  int32(d.uint32()),
+			Price:   int64(d.uint64()),
+		}
+		return Record{Cancel: c}, d.err
+	}
+	return Record{}, fmt.Errorf("unknown record kind %d", payload[0])
+}
+
+// decoder reads fields off a record, and remembers if the record was too short for them.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) take(n int) []byte {
+	if len(d.b) < n {
+		d.err = io.ErrUnexpectedEOF
+		d.b = nil
+		return make([]byte, n)
+	}
+	field := d.b[:n]
+	d.b = d.b[n:]
+	return field
+}
+
+func (d *decoder) uint16() uint16 { return binary.LittleEndian.Uint16(d.take(2)) }
+func (d *decoder) uint32() uint32 { return binary.LittleEndian.Uint32(d.take(4)) }
+func (d *decoder) uint64() uint64 { return binary.LittleEndian.Uint64(d.take(8)) }
+
+func (d *decoder) string() string {
+	return string(d.take(int(d.uint16())))
+}
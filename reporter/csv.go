@@ -0,0 +1,73 @@
+package reporter
+
+import (
+	"fmt"
+	"hash/crc32"
+	"strconv"
+)
+
+// Version of the CSV trade log format. Every segment starts with a header naming it and the columns of each record.
+const csvVersion = 1
+
+var csvHeader = fmt.Sprintf(`# trades v%d
+# TRADE,tradeId,time,symbol,aggressorSide,takerOrderId,takerUserId,makerOrderId,makerUserId,amount,price,makerFee,takerFee,crc
+# CANCEL,symbol,orderId,userId,amount,price,crc
+`, csvVersion)
+
+// csvCodec writes a record per line. The last column is the CRC32 of the line before it, in hex.
+type csvCodec struct{}
+
+func (csvCodec) ext() string    { return ".log" }
+func (csvCodec) header() []byte { return []byte(csvHeader) }
+
+func (csvCodec) trade(b []byte, t Trade) []byte {
+	start := len(b)
+	b = fmt.Appendf(b, "TRADE,%d,%d,%s,%s,%d,%d,%d,%d,%d,%d,%d,%d",
+		t.TradeId, t.Time, t.Symbol, t.AggressorSide, t.TakerOrderId, t.TakerUserId, t.MakerOrderId, t.MakerUserId, t.Amount, t.Price,
+		t.MakerFee, t.TakerFee)
+	return fmt.Appendf(b, ",%08x\n", crc32.ChecksumIEEE(b[start:]))
+}
+
+func (csvCodec) cancel(b []byte, c Cancel) []byte {
+	start := len(b)
+	b = fmt.Appendf(b, "CANCEL,%s,%d,%d,%d,%d", c.Symbol, c.OrderId, c.UserId, c.Amount, c.Price)
+	return fmt.Appendf(b, ",%08x\n", crc32.ChecksumIEEE(b[start:]))
+}
+
+func (csvCodec) intact(data []byte) (int64, uint64) {
+	return intactLines(data, func(line []byte) bool {
+		n := len(line) - 9
+		return n > 0 && line[n] == ',' && checksumMatches(line[:n], line[n+1:])
+	})
+}
+
+// intactLines returns the length of the prefix of data made of whole lines that are comments or pass check, and how
+// many of them aren't comments.
+func intactLines(data []byte, check func(line []byte) bool) (int64, uint64) {
+	var length int64
+	var records uint64
+	for len(data) > 0 {
+		end := 0
+		for end < len(data) && data[end] != '\n' {
+			end++
+		}
+		if end == len(data) {
+			break // Torn, the newline never made it.
+		}
+		line := data[:end]
+		if len(line) == 0 || line[0] != '#' {
+			if !check(line) {
+				break
+			}
+			records++
+		}
+		length += int64(end + 1)
+		data = data[end+1:]
+	}
+	return length, records
+}
+
+func checksumMatches(body, hex []byte) bool {
+	checksum, err := strconv.ParseUint(string(hex), 16, 32)
+	return err == nil && uint32(checksum) == crc32.ChecksumIEEE(body)
+}
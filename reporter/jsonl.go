@@ -0,0 +1,61 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+)
+
+// Version of the JSON lines trade log format. Every segment starts with a header object naming it.
+const jsonLinesVersion = 1
+
+var jsonLinesHeader = appendJSON(nil, struct {
+	Type    string `json:"type"`
+	Version int    `json:"version"`
+}{"header", jsonLinesVersion})
+
+// Suffix that closes every JSON lines record, with the CRC32 of the record without it in place of the x's.
+const jsonChecksumSuffix = `,"crc":"xxxxxxxx"}`
+
+// jsonLinesCodec writes a JSON object per line. Objects have the fields of Trade or Cancel, a type field telling which
+// one they are, and a crc field with the CRC32 of the object without it, in hex. The header object has the type header
+// and a version field instead.
+type jsonLinesCodec struct{}
+
+func (jsonLinesCodec) ext() string    { return ".jsonl" }
+func (jsonLinesCodec) header() []byte { return jsonLinesHeader }
+
+func (jsonLinesCodec) trade(b []byte, trade Trade) []byte {
+	return appendJSON(b, struct {
+		Type string `json:"type"`
+		Trade
+	}{"trade", trade})
+}
+
+func (jsonLinesCodec) cancel(b []byte, cancel Cancel) []byte {
+	return appendJSON(b, struct {
+		Type string `json:"type"`
+		Cancel
+	}{"cancel", cancel})
+}
+
+func appendJSON(b []byte, record any) []byte {
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		panic(err) // Records only have fields that always encode.
+	}
+	b = append(b, encoded[:len(encoded)-1]...)
+	return fmt.Appendf(b, `,"crc":"%08x"}`+"\n", crc32.ChecksumIEEE(encoded))
+}
+
+func (jsonLinesCodec) intact(data []byte) (int64, uint64) {
+	return intactLines(data, func(line []byte) bool {
+		n := len(line) - len(jsonChecksumSuffix)
+		if n <= 0 || !bytes.HasPrefix(line[n:], []byte(`,"crc":"`)) || !bytes.HasSuffix(line, []byte(`"}`)) {
+			return false
+		}
+		body := append(line[:n:n], '}')
+		return checksumMatches(body, line[n+8:len(line)-2])
+	})
+}
@@ -1,37 +1,181 @@
+// Package reporter writes the trades of the exchange, and the volume that left the books without trading, to sinks
+// such as trade logs.
 package reporter
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
-	"os"
+	"math"
 )
 
-type Reporter struct {
-	file   *os.File
-	writer *bufio.Writer
+// Trade is a trade between an incoming order, the taker, and a resting one, the maker.
+type Trade struct {
+	TradeId       uint64 `json:"tradeId"` // Increases by one with every trade of the market.
+	Time          int64  `json:"time"`
+	Symbol        string `json:"symbol"`
+	AggressorSide string `json:"aggressorSide"` // Side of the taker.
+	TakerOrderId  uint64 `json:"takerOrderId"`
+	TakerUserId   int32  `json:"takerUserId"`
+	MakerOrderId  uint64 `json:"makerOrderId"`
+	MakerUserId   int32  `json:"makerUserId"`
+	Amount        int32  `json:"amount"`
+	Price         int64  `json:"price"`
+	MakerFee      int64  `json:"makerFee"` // Negative for a rebate.
+	TakerFee      int64  `json:"takerFee"`
 }
 
-func New(filename string) (*Reporter, error) {
-	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
-	if err != nil {
-		return nil, err
+// Cancel is the remaining amount of an order that was removed from the book, so that sinks account for all volume
+// leaving it.
+type Cancel struct {
+	Symbol  string `json:"symbol"`
+	OrderId uint64 `json:"orderId"`
+	UserId  int32  `json:"userId"`
+	Amount  int32  `json:"amount"`
+	Price   int64  `json:"price"`
+}
+
+// Record is either a trade or a cancel.
+type Record struct {
+	Trade  *Trade
+	Cancel *Cancel
+}
+
+// Sink receives the records of a market. Writes may be buffered until Flush.
+type Sink interface {
+	WriteTrade(trade Trade) error
+	WriteCancel(cancel Cancel) error
+	Flush() error
+	Close() error
+}
+
+// Durable is a sink that keeps its records across restarts, such as a trade log. Its records are numbered from 1 in
+// the order they were written.
+type Durable interface {
+	Sink
+	// Written returns the number of the last record the sink holds, so that the records after it can be written again
+	// when they didn't make it before a restart.
+	Written() uint64
+	// Sync makes the records written so far durable, whatever the fsync policy.
+	Sync() error
+}
+
+// Trade log formats.
+const (
+	CSV       = "csv"
+	JSONLines = "jsonl"
+	Binary    = "binary"
+)
+
+// Open opens a trade log in the given format for appending. Its segments are named after name, followed by the range of
+// records they hold and the extension of the format.
+func Open(format, name string, options Options) (Sink, error) {
+	switch format {
+	case CSV:
+		return openSegmentedLog(csvCodec{}, name, options)
+	case JSONLines:
+		return openSegmentedLog(jsonLinesCodec{}, name, options)
+	case Binary:
+		return openSegmentedLog(binaryCodec{}, name, options)
+	}
+	return nil, fmt.Errorf("unknown trade log format %q", format)
+}
+
+// OpenAll opens a trade log for each of formats, and returns a sink writing to all of them.
+func OpenAll(formats []string, name string, options Options) (Sink, error) {
+	var sinks []Sink
+	for _, format := range formats {
+		sink, err := Open(format, name, options)
+		if err != nil {
+			Fanout(sinks...).Close()
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return Fanout(sinks...), nil
+}
+
+// fanout writes every record to all of its sinks. It's durable up to the durable sink that holds the fewest records,
+// the others skip the records they already hold when those are written again.
+type fanout struct {
+	sinks   []Sink
+	skip    []uint64
+	written uint64
+}
+
+// Fanout returns a sink that writes to all of sinks. Records still reach the other sinks if one of them fails. Sinks
+// that aren't durable get the records that are written again too.
+func Fanout(sinks ...Sink) Sink {
+	if len(sinks) == 1 {
+		return sinks[0]
+	}
+	f := &fanout{sinks: sinks, skip: make([]uint64, len(sinks)), written: math.MaxUint64}
+	for _, sink := range sinks {
+		if durable, ok := sink.(Durable); ok {
+			f.written = min(f.written, durable.Written())
+		}
+	}
+	for i, sink := range sinks {
+		if durable, ok := sink.(Durable); ok {
+			f.skip[i] = durable.Written() - f.written
+		}
+	}
+	return f
+}
+
+func (f *fanout) WriteTrade(trade Trade) error {
+	return f.write(func(sink Sink) error { return sink.WriteTrade(trade) })
+}
+
+func (f *fanout) WriteCancel(cancel Cancel) error {
+	return f.write(func(sink Sink) error { return sink.WriteCancel(cancel) })
+}
+
+func (f *fanout) write(fn func(Sink) error) error {
+	if f.written != math.MaxUint64 {
+		f.written++
+	}
+	var errs []error
+	for i, sink := range f.sinks {
+		if f.skip[i] > 0 {
+			f.skip[i]--
+			continue
+		}
+		if err := fn(sink); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	writer := bufio.NewWriter(file)
-	return &Reporter{file: file, writer: writer}, nil
+	return errors.Join(errs...)
+}
+
+// Written returns how many records all durable sinks hold, or the maximum if none of them is durable, as then there's
+// nothing to write again.
+func (f *fanout) Written() uint64 {
+	return f.written
+}
+
+func (f *fanout) Sync() error {
+	return f.each(func(sink Sink) error {
+		if durable, ok := sink.(Durable); ok {
+			return durable.Sync()
+		}
+		return nil
+	})
 }
 
-func (r *Reporter) Println(s string) error {
-	_, err := fmt.Fprintln(r.writer, s)
-	return err
+func (f *fanout) Flush() error {
+	return f.each(Sink.Flush)
 }
 
-func (r *Reporter) Flush() error {
-	return r.writer.Flush()
+func (f *fanout) Close() error {
+	return f.each(Sink.Close)
 }
 
-func (r *Reporter) Close() error {
-	if err := r.Flush(); err != nil {
-		return err
+func (f *fanout) each(fn func(Sink) error) error {
+	var errs []error
+	for _, sink := range f.sinks {
+		if err := fn(sink); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return r.file.Close()
+	return errors.Join(errs...)
 }
@@ -0,0 +1,295 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	trade  = Trade{TradeId: 3, Time: 1641016800, Symbol: "AAPL", AggressorSide: "BUY", TakerOrderId: 10, TakerUserId: 1, MakerOrderId: 7, MakerUserId: 2, Amount: 5, Price: 150, MakerFee: -1, TakerFee: 8}
+	cancel = Cancel{Symbol: "AAPL", OrderId: 7, UserId: 2, Amount: 3, Price: 150}
+)
+
+// writeAll writes a trade and a cancel to the trade log of format, and returns what it contains.
+func writeAll(t *testing.T, format string) []byte {
+	name := filepath.Join(t.TempDir(), "trades")
+	sink, err := Open(format, name, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.WriteTrade(trade); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.WriteCancel(cancel); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(name + ".*")
+	if len(files) != 1 {
+		t.Fatalf("Expected one trade log, but got %v", files)
+	}
+	contents, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return contents
+}
+
+func TestCSVFile(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(string(writeAll(t, CSV))), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "# trades v1") {
+		t.Fatalf("Expected a header and 2 records, but got %q", lines)
+	}
+	if lines[3] != "TRADE,3,1641016800,AAPL,BUY,10,1,7,2,5,150,-1,8,d98aa3e3" || lines[4] != "CANCEL,AAPL,7,2,3,150,f54495cf" {
+		t.Errorf("Unexpected records %q", lines[3:])
+	}
+}
+
+func TestJSONLinesFile(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(string(writeAll(t, JSONLines))), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and 2 records, but got %q", lines)
+	}
+	var header struct {
+		Type    string `json:"type"`
+		Version int    `json:"version"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil || header.Type != "header" || header.Version != jsonLinesVersion {
+		t.Errorf("Expected a header of version %d, but got %q", jsonLinesVersion, lines[0])
+	}
+	var decoded struct {
+		Type string `json:"type"`
+		Trade
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Type != "trade" || decoded.Trade != trade {
+		t.Errorf("Expected %+v, but got %+v", trade, decoded)
+	}
+}
+
+func TestBinaryFile(t *testing.T) {
+	records, err := ReadBinary(bytes.NewReader(writeAll(t, Binary)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || *records[0].Trade != trade || *records[1].Cancel != cancel {
+		t.Errorf("Expected the trade and the cancel back, but got %v", records)
+	}
+}
+
+func TestBinaryFormatVersion(t *testing.T) {
+	contents := writeAll(t, Binary)
+	if !bytes.HasPrefix(contents, binaryHeader) {
+		t.Fatalf("Expected the segment to start with the format record, but got %x", contents)
+	}
+
+	newer, start := beginFrame(nil, binaryFormat)
+	newer = endFrame(binary.LittleEndian.AppendUint16(newer, binaryVersion+1), start)
+	if _, err := ReadBinary(bytes.NewReader(append(newer, contents[len(binaryHeader):]...))); err == nil {
+		t.Errorf("Expected a newer version to be rejected")
+	}
+	if _, err := ReadBinary(bytes.NewReader(contents[len(binaryHeader):])); err == nil {
+		t.Errorf("Expected a segment without a format record to be rejected")
+	}
+}
+
+// writeTrades opens the trade log, writes count trades to it and closes it.
+func writeTrades(t *testing.T, format, name string, options Options, count int) {
+	sink, err := Open(format, name, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < count; i++ {
+		if err := sink.WriteTrade(trade); err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTornRecordIsTrimmed(t *testing.T) {
+	for _, format := range []string{CSV, JSONLines, Binary} {
+		t.Run(format, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "trades")
+			writeTrades(t, format, name, Options{}, 2)
+			files, _ := filepath.Glob(name + ".*")
+			intact, _ := os.ReadFile(files[0])
+
+			// The second record is torn, and garbage follows it.
+			torn := append(intact[:len(intact)-3:len(intact)-3], "xyz"...)
+			os.WriteFile(files[0], torn, 0666)
+			writeTrades(t, format, name, Options{}, 1)
+
+			contents, _ := os.ReadFile(files[0])
+			if !bytes.Equal(contents, intact) {
+				t.Errorf("Expected the torn record to be replaced, but got %q", contents)
+			}
+		})
+	}
+}
+
+func TestSegmentWithoutHeaderIsRejected(t *testing.T) {
+	for _, format := range []string{CSV, JSONLines, Binary} {
+		t.Run(format, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "trades")
+			writeTrades(t, format, name, Options{}, 1)
+			files, _ := filepath.Glob(name + ".*")
+			contents, _ := os.ReadFile(files[0])
+
+			// A header torn while the segment was created is written again.
+			os.WriteFile(files[0], contents[:3], 0666)
+			writeTrades(t, format, name, Options{}, 1)
+			if got, _ := os.ReadFile(files[0]); !bytes.Equal(got, contents) {
+				t.Errorf("Expected the segment to be started over, but got %q", got)
+			}
+
+			os.WriteFile(files[0], contents[len(contents)/2:], 0666)
+			if _, err := Open(format, name, Options{}); err == nil {
+				t.Errorf("Expected a segment that doesn't start with the header to be rejected")
+			}
+		})
+	}
+}
+
+func TestRotation(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "trades")
+	options := Options{MaxSegmentSize: 1, Fsync: FsyncNever}
+	writeTrades(t, Binary, name, options, 3)
+	writeTrades(t, Binary, name, options, 1)
+
+	var files []string
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		files = append(files, entry.Name())
+	}
+	expected := []string{
+		"trades.00000000000000000001-00000000000000000001.bin",
+		"trades.00000000000000000002-00000000000000000002.bin",
+		"trades.00000000000000000003-00000000000000000003.bin",
+		"trades.00000000000000000004.bin",
+	}
+	if strings.Join(files, " ") != strings.Join(expected, " ") {
+		t.Fatalf("Expected segments %v, but got %v", expected, files)
+	}
+	for _, file := range files {
+		contents, _ := os.ReadFile(filepath.Join(dir, file))
+		if records, err := ReadBinary(bytes.NewReader(contents)); err != nil || len(records) != 1 {
+			t.Errorf("Expected a trade in %s, but got %v, %v", file, records, err)
+		}
+	}
+}
+
+func TestFsyncInterval(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "trades")
+	sink, err := Open(CSV, name, Options{Fsync: FsyncInterval, FsyncInterval: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.WriteTrade(trade)
+	sink.Flush()
+
+	// The timer is cleared by the fsync it schedules.
+	log := sink.(*segmentedLog)
+	pending := func() bool {
+		log.mutex.Lock()
+		defer log.mutex.Unlock()
+		return log.timer != nil
+	}
+	if !pending() {
+		t.Fatalf("Expected Flush to schedule an fsync")
+	}
+	for deadline := time.Now().Add(time.Second); pending(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the fsync to run within a second")
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseFsyncPolicy(t *testing.T) {
+	tests := []struct {
+		in       string
+		policy   FsyncPolicy
+		interval time.Duration
+		err      bool
+	}{
+		{"batch", FsyncEveryBatch, 0, false},
+		{"never", FsyncNever, 0, false},
+		{"50ms", FsyncInterval, 50 * time.Millisecond, false},
+		{"0s", 0, 0, true},
+		{"always", 0, 0, true},
+	}
+	for _, tt := range tests {
+		policy, interval, err := ParseFsyncPolicy(tt.in)
+		if (err != nil) != tt.err || policy != tt.policy || interval != tt.interval {
+			t.Errorf("ParseFsyncPolicy(%q) = %v, %v, %v", tt.in, policy, interval, err)
+		}
+	}
+}
+
+func TestFanoutToRing(t *testing.T) {
+	small, large := NewRing(2), NewRing(8)
+	sink := Fanout(small, large)
+	for i := 0; i < 3; i++ {
+		trade := trade
+		trade.TradeId = uint64(i)
+		sink.WriteTrade(trade)
+	}
+
+	if records := large.Records(); len(records) != 3 {
+		t.Errorf("Expected all 3 trades, but got %d", len(records))
+	}
+	if records := small.Records(); len(records) != 2 || records[0].Trade.TradeId != 1 || records[1].Trade.TradeId != 2 {
+		t.Errorf("Expected the ring to keep the latest 2 trades, oldest first, but got %v", records)
+	}
+}
+
+func TestFanoutWritesLostRecordsAgain(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "trades")
+	writeTrades(t, CSV, name, Options{}, 3)
+	writeTrades(t, JSONLines, name, Options{}, 1)
+
+	sink, err := OpenAll([]string{CSV, JSONLines}, name, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	durable, ok := sink.(Durable)
+	if !ok || durable.Written() != 1 {
+		t.Fatalf("Expected the logs to hold 1 record in common, but got %v", sink)
+	}
+	// Records 2 and 3 are written again, and only the log that lost them takes them.
+	for i := 0; i < 3; i++ {
+		sink.WriteTrade(trade)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{CSV, JSONLines} {
+		log, err := Open(format, name, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written := log.(Durable).Written(); written != 4 {
+			t.Errorf("Expected the %s log to hold 4 records, but got %d", format, written)
+		}
+		log.Close()
+	}
+}
@@ -0,0 +1,48 @@
+package reporter
+
+import "sync"
+
+// Ring keeps the latest records in memory, which is handy for tests.
+type Ring struct {
+	mutex   sync.Mutex
+	records []Record
+	next    int // Where the next record goes once the ring is full.
+}
+
+// NewRing returns a ring that keeps up to capacity records.
+func NewRing(capacity int) *Ring {
+	return &Ring{records: make([]Record, 0, max(capacity, 1))}
+}
+
+func (r *Ring) WriteTrade(trade Trade) error {
+	r.add(Record{Trade: &trade})
+	return nil
+}
+
+func (r *Ring) WriteCancel(cancel Cancel) error {
+	r.add(Record{Cancel: &cancel})
+	return nil
+}
+
+func (r *Ring) add(record Record) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.records) < cap(r.records) {
+		r.records = append(r.records, record)
+		return
+	}
+	r.records[r.next] = record
+	r.next = (r.next + 1) % len(r.records)
+}
+
+func (r *Ring) Flush() error { return nil }
+func (r *Ring) Close() error { return nil }
+
+// Records returns the records in the ring, oldest first.
+func (r *Ring) Records() []Record {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append(append([]Record(nil), r.records[r.next:]...), r.records[:r.next]...)
+}
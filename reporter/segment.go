@@ -0,0 +1,307 @@
+package reporter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FsyncPolicy tells when a trade log makes its records durable.
+type FsyncPolicy int
+
+const (
+	FsyncEveryBatch FsyncPolicy = iota // Fsync on every Flush, which the engine calls once per batch of records.
+	FsyncInterval                      // Fsync once Options.FsyncInterval passed since the first unsynced Flush.
+	FsyncNever                         // Leave it to the OS, only segments that are rotated or closed are fsynced.
+)
+
+// Options tune the durability and rotation of trade logs. The zero value fsyncs every batch and never rotates.
+type Options struct {
+	Fsync          FsyncPolicy
+	FsyncInterval  time.Duration
+	MaxSegmentSize int64         // Rotate once the segment is this many bytes, 0 for no limit.
+	MaxSegmentAge  time.Duration // Rotate once the segment was written this long, 0 for no limit.
+}
+
+// ParseFsyncPolicy parses "batch", "never", or a duration such as "100ms" that is the fsync interval.
+func ParseFsyncPolicy(s string) (FsyncPolicy, time.Duration, error) {
+	switch s {
+	case "batch":
+		return FsyncEveryBatch, 0, nil
+	case "never":
+		return FsyncNever, 0, nil
+	}
+	interval, err := time.ParseDuration(s)
+	if err != nil || interval <= 0 {
+		return 0, 0, fmt.Errorf("fsync policy %q is not batch, never or a positive duration", s)
+	}
+	return FsyncInterval, interval, nil
+}
+
+// codec encodes the records of a trade log format, each one together with its checksum.
+type codec interface {
+	ext() string
+	header() []byte // Written at the start of every segment.
+	trade(b []byte, trade Trade) []byte
+	cancel(b []byte, cancel Cancel) []byte
+	// intact returns the length of the prefix of data made of whole records with matching checksums, and how many
+	// records there are in it. data doesn't include the header.
+	intact(data []byte) (int64, uint64)
+}
+
+// segmentedLog is an append-only trade log split into segments. Records are numbered from 1 across segments, and
+// segments are named after the range of records they hold: NAME.FIRST-LAST.EXT once they are rotated, and NAME.FIRST.EXT
+// while they are written to.
+type segmentedLog struct {
+	mutex   sync.Mutex // Guards against the fsync timer.
+	codec   codec
+	name    string
+	options Options
+
+	file    *os.File
+	writer  *bufio.Writer
+	first   uint64 // Number of the first record of the segment.
+	next    uint64 // Number of the next record.
+	size    int64
+	created time.Time
+	timer   *time.Timer // Pending fsync of the FsyncInterval policy.
+	buf     []byte
+}
+
+// openSegmentedLog continues the segment that was written to last, after trimming a torn record at its end, or starts
+// the first segment.
+func openSegmentedLog(codec codec, name string, options Options) (*segmentedLog, error) {
+	l := &segmentedLog{codec: codec, name: name, options: options, first: 1}
+	active, err := l.scan()
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return l, l.create()
+	}
+
+	filename := l.segment(l.first, 0)
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	// A segment that doesn't start with the whole header was torn while it was created, and is started over.
+	var length int64
+	var records uint64
+	if header := codec.header(); bytes.HasPrefix(data, header) {
+		length, records = codec.intact(data[len(header):])
+		length += int64(len(header))
+	} else if !bytes.HasPrefix(header, data) {
+		return nil, fmt.Errorf("trade log segment %s doesn't start with the header of its format", filename)
+	}
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Truncate(length); err != nil {
+		file.Close()
+		return nil, err
+	}
+	l.file, l.writer = file, bufio.NewWriter(file)
+	l.next, l.size, l.created = l.first+records, length, time.Now()
+	if length == 0 {
+		l.writer.Write(codec.header())
+		l.size = int64(len(codec.header()))
+	}
+	return l, nil
+}
+
+// scan finds where the log continues: the segment written to last if there is one, or after the last rotated segment.
+func (l *segmentedLog) scan() (bool, error) {
+	entries, err := os.ReadDir(filepath.Dir(l.name))
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	prefix, active, last := filepath.Base(l.name)+".", false, uint64(0)
+	for _, entry := range entries {
+		span, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok {
+			continue
+		}
+		if span, ok = strings.CutSuffix(span, l.codec.ext()); !ok {
+			continue
+		}
+		from, to, rotated := strings.Cut(span, "-")
+		first, err := strconv.ParseUint(from, 10, 64)
+		if err != nil {
+			continue
+		}
+		if !rotated {
+			if active {
+				return false, fmt.Errorf("trade log %s has several segments being written to", l.name)
+			}
+			l.first, active = first, true
+			continue
+		}
+		if to, err := strconv.ParseUint(to, 10, 64); err == nil {
+			last = max(last, to)
+		}
+	}
+	if !active {
+		l.first = last + 1
+	}
+	l.next = l.first
+	return active, nil
+}
+
+// segment returns the name of the segment holding records first to last, with last 0 for the one being written to.
+func (l *segmentedLog) segment(first, last uint64) string {
+	if last == 0 {
+		return fmt.Sprintf("%s.%020d%s", l.name, first, l.codec.ext())
+	}
+	return fmt.Sprintf("%s.%020d-%020d%s", l.name, first, last, l.codec.ext())
+}
+
+// create starts a new segment with the next record. The directory is synced, so that the segment, and the rename of the
+// one before it, survive a crash.
+func (l *segmentedLog) create() error {
+	file, err := os.OpenFile(l.segment(l.next, 0), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	l.file, l.writer = file, bufio.NewWriter(file)
+	l.first, l.created = l.next, time.Now()
+	n, err := l.writer.Write(l.codec.header())
+	l.size = int64(n)
+	if err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(l.name))
+}
+
+// syncDir makes the creation and renaming of files within dir durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// rotate seals the segment under the name of its records once it's full or old enough, and starts the next one.
+func (l *segmentedLog) rotate() error {
+	if l.next == l.first {
+		return nil
+	}
+	full := l.options.MaxSegmentSize > 0 && l.size >= l.options.MaxSegmentSize
+	old := l.options.MaxSegmentAge > 0 && time.Since(l.created) >= l.options.MaxSegmentAge
+	if !full && !old {
+		return nil
+	}
+	if err := l.closeSegment(); err != nil {
+		return err
+	}
+	if err := os.Rename(l.segment(l.first, 0), l.segment(l.first, l.next-1)); err != nil {
+		return err
+	}
+	return l.create()
+}
+
+func (l *segmentedLog) WriteTrade(trade Trade) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.write(l.codec.trade(l.buf[:0], trade))
+}
+
+func (l *segmentedLog) WriteCancel(cancel Cancel) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.write(l.codec.cancel(l.buf[:0], cancel))
+}
+
+func (l *segmentedLog) write(record []byte) error {
+	l.buf = record
+	if err := l.rotate(); err != nil {
+		return err
+	}
+	n, err := l.writer.Write(record)
+	l.size += int64(n)
+	if err != nil {
+		return err
+	}
+	l.next++
+	return nil
+}
+
+// Flush writes buffered records to the file, and fsyncs it as the fsync policy says.
+func (l *segmentedLog) Flush() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if err := l.writer.Flush(); err != nil {
+		return err
+	}
+	switch l.options.Fsync {
+	case FsyncEveryBatch:
+		return l.file.Sync()
+	case FsyncInterval:
+		if l.timer == nil {
+			l.timer = time.AfterFunc(l.options.FsyncInterval, l.syncLater)
+		}
+	}
+	return nil
+}
+
+func (l *segmentedLog) syncLater() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.timer = nil
+	if l.file != nil {
+		l.file.Sync()
+	}
+}
+
+// Written returns the number of the last record of the log.
+func (l *segmentedLog) Written() uint64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.next - 1
+}
+
+// Sync writes buffered records to the file and fsyncs it.
+func (l *segmentedLog) Sync() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if err := l.writer.Flush(); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+// closeSegment makes the records of the segment durable and closes it.
+func (l *segmentedLog) closeSegment() error {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	err := l.writer.Flush()
+	if err == nil {
+		err = l.file.Sync()
+	}
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	l.file = nil
+	return err
+}
+
+// Close closes the segment being written to, which is continued when the log is opened again.
+func (l *segmentedLog) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.closeSegment()
+}
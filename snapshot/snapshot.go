@@ -0,0 +1,81 @@
+package snapshot
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+)
+
+// File layout: magic, format version, CRC32 of the payload, payload length, payload.
+const (
+	magic      = "EXSN"
+	headerSize = len(magic) + 4 + 4 + 8
+)
+
+var ErrCorrupt = errors.New("snapshot is corrupted")
+
+// Write stores payload in filename. The file is written under a temporary name and renamed once it's synced, so a
+// crash never leaves a partially written snapshot behind under the final name.
+func Write(filename string, version uint32, payload []byte) error {
+	header := make([]byte, headerSize)
+	copy(header, magic)
+	binary.LittleEndian.PutUint32(header[4:8], version)
+	binary.LittleEndian.PutUint32(header[8:12], crc32.ChecksumIEEE(payload))
+	binary.LittleEndian.PutUint64(header[12:20], uint64(len(payload)))
+
+	tmp := filename + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(header); err != nil {
+		file.Close()
+		return err
+	}
+	if _, err := file.Write(payload); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(filename))
+}
+
+// Read returns the format version and payload stored in filename, or ErrCorrupt if it fails verification.
+func Read(filename string) (uint32, []byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < headerSize || string(data[:len(magic)]) != magic {
+		return 0, nil, ErrCorrupt
+	}
+	version := binary.LittleEndian.Uint32(data[4:8])
+	checksum := binary.LittleEndian.Uint32(data[8:12])
+	size := binary.LittleEndian.Uint64(data[12:20])
+	payload := data[headerSize:]
+	if uint64(len(payload)) != size || crc32.ChecksumIEEE(payload) != checksum {
+		return 0, nil, ErrCorrupt
+	}
+	return version, payload, nil
+}
+
+// syncDir makes a rename within dir durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,43 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteRead(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.snapshot")
+	if err := Write(filename, 3, []byte("state")); err != nil {
+		t.Fatal(err)
+	}
+
+	version, payload, err := Read(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 3 || string(payload) != "state" {
+		t.Errorf("Expected version 3 with payload state, but got %d with %q", version, payload)
+	}
+}
+
+func TestCorruptedSnapshot(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.snapshot")
+	if err := Write(filename, 1, []byte("state")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(filename, data, 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := Read(filename); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expected corruption to be detected, but got %v", err)
+	}
+}